@@ -0,0 +1,147 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errBuildApplyConfiguration = "cannot build the apply configuration of the override"
+	errFmtBuildManifest        = "cannot build the applied manifest of %s"
+)
+
+// ToApplyConfiguration returns the fully specified intent of this Override
+// for the referenced object, to be applied with server-side apply.
+func (o *Override) ToApplyConfiguration(ref ObjectReference) (*unstructured.Unstructured, error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildApplyConfiguration)
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(b, &u.Object); err != nil {
+		return nil, errors.Wrap(err, errBuildApplyConfiguration)
+	}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	u.SetName(ref.Name)
+	u.SetNamespace(ptr.Deref(ref.Namespace, ""))
+	return u, nil
+}
+
+// AppliedManifests returns the fully specified intents that this
+// InControlPlaneOverride applies to each of the supplied objects of its
+// resolved target hierarchy, in the order of the objects, e.g., to be recorded
+// in an audit log.
+func (o *InControlPlaneOverride) AppliedManifests(targets []ObjectReference) ([]unstructured.Unstructured, error) {
+	manifests := make([]unstructured.Unstructured, 0, len(targets))
+	for _, t := range targets {
+		u, err := o.Spec.Override.ToApplyConfiguration(t)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtBuildManifest, objectName(t))
+		}
+		manifests = append(manifests, *u)
+	}
+	return manifests, nil
+}
+
+// ApplyPatches applies the override of the supplied InControlPlaneOverride to
+// each of the targets using server-side apply with the override's field
+// manager, forcing the ownership of the overridden fields so that they are
+// taken over from their current managers rather than reported as conflicts,
+// running at most maxConcurrent applies in parallel. It returns the
+// status of each applied target, in the order of the targets. Failed applies
+// are reported as classified by PatchFailure rather than aborting the batch.
+// The applies of a dry run InControlPlaneOverride are only run as server-side
+// dry runs, and the targets that would be patched are reported as Planned.
+// If the context is canceled, no further applies are started and only the
+// statuses of the targets processed so far are returned.
+func ApplyPatches(ctx context.Context, c client.Client, o *InControlPlaneOverride, targets []corev1.TypedObjectReference, maxConcurrent int) []PatchedObjectStatus {
+	results := make([]*PatchedObjectStatus, len(targets))
+	sem := make(chan struct{}, max(maxConcurrent, 1))
+	var wg sync.WaitGroup
+dispatch:
+	for i, t := range targets {
+		// select picks randomly among ready cases, so check for
+		// cancellation first not to start applies on a canceled context.
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int, t corev1.TypedObjectReference) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s := applyPatch(ctx, c, o, t)
+			results[i] = &s
+		}(i, t)
+	}
+	wg.Wait()
+
+	statuses := make([]PatchedObjectStatus, 0, len(targets))
+	for _, s := range results {
+		if s != nil {
+			statuses = append(statuses, *s)
+		}
+	}
+	return statuses
+}
+
+// applyPatch applies the override of the supplied InControlPlaneOverride to
+// the target and returns the resulting status.
+func applyPatch(ctx context.Context, c client.Client, o *InControlPlaneOverride, t corev1.TypedObjectReference) PatchedObjectStatus {
+	ref := ObjectReference{Kind: t.Kind, Name: t.Name, Namespace: t.Namespace}
+	v, err := ResolveAPIVersion(t, c.RESTMapper())
+	if err != nil {
+		return PatchFailure(ref, nil, err)
+	}
+	ref.APIVersion = v
+	u, err := o.Spec.Override.ToApplyConfiguration(ref)
+	if err != nil {
+		return PatchFailure(ref, nil, err)
+	}
+	hash, err := o.Spec.Override.Hash()
+	if err != nil {
+		return PatchFailure(ref, nil, err)
+	}
+	opts := []client.PatchOption{client.FieldOwner(o.FieldManager()), client.ForceOwnership}
+	if o.IsDryRun() {
+		opts = append(opts, client.DryRunAll)
+	}
+	if err := c.Patch(ctx, u, client.Apply, opts...); err != nil {
+		return PatchFailure(ref, nil, err)
+	}
+	var uid *types.UID
+	if u.GetUID() != "" {
+		uid = ptr.To(u.GetUID())
+	}
+	if o.IsDryRun() {
+		return PatchPlanned(ref, uid)
+	}
+	return PatchSuccess(ref, uid, hash)
+}
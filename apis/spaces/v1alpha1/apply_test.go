@@ -0,0 +1,329 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// mapperClient is a mock client with a REST mapper.
+type mapperClient struct {
+	*test.MockClient
+	mapper meta.RESTMapper
+}
+
+func (c *mapperClient) RESTMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+func TestToApplyConfiguration(t *testing.T) {
+	ref := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+
+	cases := map[string]struct {
+		reason   string
+		override Override
+		want     map[string]any
+	}{
+		"Empty": {
+			reason: "An empty override should only identify the target object.",
+			want: map[string]any{
+				"apiVersion": "example.org/v1alpha1",
+				"kind":       "Network",
+				"metadata":   map[string]any{"name": "network", "namespace": "default"},
+			},
+		},
+		"Annotations": {
+			reason: "The override's annotations should be part of the intent.",
+			override: Override{Metadata: &MetadataPatch{Annotations: map[string]string{
+				AnnotationKeyPaused: "true",
+			}}},
+			want: map[string]any{
+				"apiVersion": "example.org/v1alpha1",
+				"kind":       "Network",
+				"metadata": map[string]any{
+					"name":        "network",
+					"namespace":   "default",
+					"annotations": map[string]any{AnnotationKeyPaused: "true"},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, err := tc.override.ToApplyConfiguration(ref)
+			if err != nil {
+				t.Fatalf("\n%s\nToApplyConfiguration(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, u.Object); diff != "" {
+				t.Errorf("\n%s\nToApplyConfiguration(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyPatches(t *testing.T) {
+	gv := schema.GroupVersion{Group: "example.org", Version: "v1alpha1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gv})
+	mapper.Add(gv.WithKind("XNetwork"), meta.RESTScopeRoot)
+
+	o := NewPauseOverride("ctp", ObjectReference{APIVersion: gv.String(), Kind: "XNetwork", Name: "a"}, true)
+	o.SetNamespace("default")
+	o.SetName("pause")
+	hash, err := o.Spec.Override.Hash()
+	if err != nil {
+		t.Fatalf("Hash(): %v", err)
+	}
+
+	targetA := corev1.TypedObjectReference{APIGroup: ptr.To(gv.Group), Kind: "XNetwork", Name: "a"}
+	targetB := corev1.TypedObjectReference{APIGroup: ptr.To(gv.Group), Kind: "XNetwork", Name: "b"}
+	refA := ObjectReference{APIVersion: gv.String(), Kind: "XNetwork", Name: "a"}
+	refB := ObjectReference{APIVersion: gv.String(), Kind: "XNetwork", Name: "b"}
+	errConflict := apierrors.NewConflict(schema.GroupResource{Group: gv.Group, Resource: "xnetworks"}, "b", errors.New("boom"))
+
+	// patch returns a mock that applies successfully, setting the UID of the object to its
+	// name, and fails applies to object b with errConflict. It checks that
+	// applies force ownership and are dry runs if and only if dryRun is true.
+	patch := func(dryRun bool) test.MockPatchFn {
+		return func(_ context.Context, obj client.Object, p client.Patch, opts ...client.PatchOption) error {
+			if p != client.Apply {
+				return errors.Errorf("unexpected patch type %s", p.Type())
+			}
+			po := &client.PatchOptions{}
+			po.ApplyOptions(opts)
+			if po.FieldManager != o.FieldManager() {
+				return errors.Errorf("unexpected field manager %q", po.FieldManager)
+			}
+			if po.Force == nil || !*po.Force {
+				return errors.New("the apply does not force ownership")
+			}
+			if dryRun != (len(po.DryRun) > 0) {
+				return errors.Errorf("unexpected dry run options %v", po.DryRun)
+			}
+			if obj.GetAnnotations()[AnnotationKeyPaused] != "true" {
+				return errors.New("the override has not been applied")
+			}
+			if obj.GetName() == "b" {
+				return errConflict
+			}
+			obj.SetUID(types.UID(obj.GetName()))
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason   string
+		ctx      func() context.Context
+		dryRun   *bool
+		targets  []corev1.TypedObjectReference
+		parallel int
+		want     []PatchedObjectStatus
+	}{
+		"DryRun": {
+			reason:   "The targets of a dry run should be reported as planned rather than patched.",
+			ctx:      context.Background,
+			dryRun:   ptr.To(true),
+			targets:  []corev1.TypedObjectReference{targetA, targetB},
+			parallel: 2,
+			want: []PatchedObjectStatus{
+				PatchPlanned(refA, ptr.To(types.UID("a"))),
+				PatchFailure(refB, nil, errConflict),
+			},
+		},
+		"Success": {
+			reason:   "Successfully applied targets should be reported as such, with their UIDs and the applied hash.",
+			ctx:      context.Background,
+			targets:  []corev1.TypedObjectReference{targetA},
+			parallel: 2,
+			want:     []PatchedObjectStatus{PatchSuccess(refA, ptr.To(types.UID("a")), hash)},
+		},
+		"PartialFailure": {
+			reason:   "A failed apply should be reported without affecting the other targets.",
+			ctx:      context.Background,
+			targets:  []corev1.TypedObjectReference{targetA, targetB},
+			parallel: 1,
+			want: []PatchedObjectStatus{
+				PatchSuccess(refA, ptr.To(types.UID("a")), hash),
+				PatchFailure(refB, nil, errConflict),
+			},
+		},
+		"UnknownKind": {
+			reason:   "A target whose API version cannot be resolved should be reported as failed.",
+			ctx:      context.Background,
+			targets:  []corev1.TypedObjectReference{{APIGroup: ptr.To(gv.Group), Kind: "XCluster", Name: "c"}},
+			parallel: 1,
+			want: []PatchedObjectStatus{
+				PatchFailure(ObjectReference{Kind: "XCluster", Name: "c"}, nil,
+					errors.Wrapf(&meta.NoResourceMatchError{PartialResource: schema.GroupVersionResource{Group: gv.Group, Resource: "XCluster"}},
+						errFmtResolveVersion, schema.GroupKind{Group: gv.Group, Kind: "XCluster"})),
+			},
+		},
+		"Canceled": {
+			reason: "No targets should be applied once the context is canceled.",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			targets:  []corev1.TypedObjectReference{targetA, targetB},
+			parallel: 1,
+			want:     []PatchedObjectStatus{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := o.DeepCopy()
+			o.Spec.DryRun = tc.dryRun
+			c := &mapperClient{MockClient: &test.MockClient{MockPatch: patch(o.IsDryRun())}, mapper: mapper}
+			got := ApplyPatches(tc.ctx(), c, o, tc.targets, tc.parallel)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nApplyPatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// TestApplyPatchesUnpause checks the result of applying an unpause override
+// to a paused object.
+func TestApplyPatchesUnpause(t *testing.T) {
+	gv := schema.GroupVersion{Group: "example.org", Version: "v1alpha1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gv})
+	mapper.Add(gv.WithKind("XNetwork"), meta.RESTScopeRoot)
+
+	ref := ObjectReference{APIVersion: gv.String(), Kind: "XNetwork", Name: "a"}
+	o := NewUnpauseOverride("ctp", ref, false)
+	hash, err := o.Spec.Override.Hash()
+	if err != nil {
+		t.Fatalf("Hash(): %v", err)
+	}
+
+	// The mock merges the annotations of the applied intent into the stored
+	// object, as server-side apply does for the fields of the intent.
+	stored := &unstructured.Unstructured{}
+	stored.SetAnnotations(map[string]string{AnnotationKeyPaused: "true", "example.org/owner": "team-a"})
+	c := &mapperClient{mapper: mapper, MockClient: &test.MockClient{
+		MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			annotations := stored.GetAnnotations()
+			for k, v := range obj.GetAnnotations() {
+				annotations[k] = v
+			}
+			stored.SetAnnotations(annotations)
+			obj.SetUID("a")
+			return nil
+		},
+	}}
+
+	target := corev1.TypedObjectReference{APIGroup: ptr.To(gv.Group), Kind: "XNetwork", Name: "a"}
+	got := ApplyPatches(context.Background(), c, o, []corev1.TypedObjectReference{target}, 1)
+	if diff := cmp.Diff([]PatchedObjectStatus{PatchSuccess(ref, ptr.To(types.UID("a")), hash)}, got); diff != "" {
+		t.Errorf("ApplyPatches(...): -want, +got:\n%s", diff)
+	}
+	want := map[string]string{AnnotationKeyPaused: "", "example.org/owner": "team-a"}
+	if diff := cmp.Diff(want, stored.GetAnnotations()); diff != "" {
+		t.Errorf("ApplyPatches(...): -want annotations, +got annotations:\n%s", diff)
+	}
+	if o.Spec.Override.Changes(stored) {
+		t.Error("Changes(...): want false for the unpaused object, got true")
+	}
+}
+
+func TestApplyPatchesConcurrency(t *testing.T) {
+	gv := schema.GroupVersion{Group: "example.org", Version: "v1alpha1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gv})
+	mapper.Add(gv.WithKind("XNetwork"), meta.RESTScopeRoot)
+	o := NewPauseOverride("ctp", ObjectReference{APIVersion: gv.String(), Kind: "XNetwork", Name: "network"}, true)
+
+	targets := make([]corev1.TypedObjectReference, 20)
+	for i := range targets {
+		targets[i] = corev1.TypedObjectReference{APIGroup: ptr.To(gv.Group), Kind: "XNetwork", Name: string(rune('a' + i))}
+	}
+
+	const maxConcurrent = 3
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	c := &mapperClient{mapper: mapper, MockClient: &test.MockClient{
+		MockPatch: func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			mu.Lock()
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+			return nil
+		},
+	}}
+
+	got := ApplyPatches(context.Background(), c, o, targets, maxConcurrent)
+	if len(got) != len(targets) {
+		t.Errorf("ApplyPatches(...): want %d statuses, got %d", len(targets), len(got))
+	}
+	for i, s := range got {
+		if s.Name != targets[i].Name || s.Status != PatchStateSuccess {
+			t.Errorf("ApplyPatches(...): want status %d to be a success for %s, got %s", i, targets[i].Name, s.String())
+		}
+	}
+	if peak > maxConcurrent {
+		t.Errorf("ApplyPatches(...): want at most %d concurrent applies, got %d", maxConcurrent, peak)
+	}
+}
+
+func TestAppliedManifests(t *testing.T) {
+	claim := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+	xr := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network-x7k2p"}
+	vpc := ObjectReference{APIVersion: "ec2.aws.upbound.io/v1beta1", Kind: "VPC", Name: "network-x7k2p-vpc"}
+	o := NewPauseOverride("ctp", claim, true)
+
+	got, err := o.AppliedManifests([]ObjectReference{claim, xr, vpc})
+	if err != nil {
+		t.Fatalf("AppliedManifests(...): unexpected error: %v", err)
+	}
+	paused := map[string]any{AnnotationKeyPaused: "true"}
+	want := []unstructured.Unstructured{
+		{Object: map[string]any{
+			"apiVersion": "example.org/v1alpha1",
+			"kind":       "Network",
+			"metadata":   map[string]any{"name": "network", "namespace": "default", "annotations": paused},
+		}},
+		{Object: map[string]any{
+			"apiVersion": "example.org/v1alpha1",
+			"kind":       "XNetwork",
+			"metadata":   map[string]any{"name": "network-x7k2p", "annotations": paused},
+		}},
+		{Object: map[string]any{
+			"apiVersion": "ec2.aws.upbound.io/v1beta1",
+			"kind":       "VPC",
+			"metadata":   map[string]any{"name": "network-x7k2p-vpc", "annotations": paused},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AppliedManifests(...): -want, +got:\n%s", diff)
+	}
+}
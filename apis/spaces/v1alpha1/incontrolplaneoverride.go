@@ -0,0 +1,845 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	errEmptyControlPlaneName     = "controlPlaneName cannot be empty"
+	errEmptyTargetAPIVersion     = "targetRef.apiVersion cannot be empty"
+	errEmptyTargetKind           = "targetRef.kind cannot be empty"
+	errEmptyTargetName           = "targetRef.name cannot be empty"
+	errFmtInvalidPropagation     = "invalid propagationPolicy %q: must be one of None, Ascending or Descending"
+	errFmtInvalidDeletion        = "invalid deletionPolicy %q: must be one of RollBack or Keep"
+	errFmtAnnotationNotAllowed   = "annotation %q is not allowed to be overridden"
+	errTargetNamespaceRequired   = "targetRef.namespace must be set for a namespaced target such as a claim"
+	errTargetNamespaceNotAllowed = "targetRef.namespace must be empty for a cluster-scoped target such as a composite resource"
+	errHashOverride              = "cannot hash the override"
+	errFmtSystemTarget           = "targetRef cannot refer to an object in the system namespace %q"
+	errFmtInvalidPatchState      = "invalid patch state %q: must be one of Success, Pending, Skipped, Error or Planned"
+	errFmtDryRunPatched          = "dry run override has patched %s"
+	errFmtDuplicateObjectRef     = "duplicate object reference to %s in status"
+	errFmtDuplicateUID           = "duplicate UID %q in status for %s and %s"
+	errFmtPatchStateTransition   = "invalid patch state transition from %s to %s"
+	errFmtTooManyTargets         = "targetRef resolves to %d objects, exceeding the maximum of %d"
+)
+
+var (
+	// ErrTargetNamespaceRequired is returned when a namespaced target, such as
+	// a claim, is referenced without a namespace.
+	ErrTargetNamespaceRequired = errors.New(errTargetNamespaceRequired)
+	// ErrTargetNamespaceNotAllowed is returned when a cluster-scoped target,
+	// such as a composite resource, is referenced with a namespace.
+	ErrTargetNamespaceNotAllowed = errors.New(errTargetNamespaceNotAllowed)
+)
+
+// A ValidateOption configures the checks run by Validate.
+// +kubebuilder:object:generate=false
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	namespaced *bool
+}
+
+// WithTargetScope makes Validate also check that the namespace of the target
+// reference is consistent with the supplied scope of the target's kind, as
+// ValidateTargetScope does. The scope is only known to the ControlPlane
+// serving the kind, e.g., as resolved by its REST mapper.
+func WithTargetScope(namespaced bool) ValidateOption {
+	return func(o *validateOptions) {
+		o.namespaced = ptr.To(namespaced)
+	}
+}
+
+// Validate checks the InControlPlaneOverrideSpec against the constraints
+// enforced by the API server, so that clients can catch invalid overrides
+// before they are submitted. Whether the namespace of the target reference is
+// consistent with the scope of the target's kind is only checked if the
+// scope is supplied using WithTargetScope.
+func (s *InControlPlaneOverrideSpec) Validate(opts ...ValidateOption) error {
+	o := &validateOptions{}
+	for _, fn := range opts {
+		fn(o)
+	}
+	if s.ControlPlaneName == "" {
+		return errors.New(errEmptyControlPlaneName)
+	}
+	if s.TargetRef.APIVersion == "" {
+		return errors.New(errEmptyTargetAPIVersion)
+	}
+	if s.TargetRef.Kind == "" {
+		return errors.New(errEmptyTargetKind)
+	}
+	if s.TargetRef.Name == "" {
+		return errors.New(errEmptyTargetName)
+	}
+	if o.namespaced != nil {
+		if err := s.ValidateTargetScope(*o.namespaced); err != nil {
+			return err
+		}
+	}
+	switch s.PropagationPolicy {
+	case "", PatchPropagateNone, PatchPropagateAscending, PatchPropagateDescending:
+	default:
+		return errors.Errorf(errFmtInvalidPropagation, s.PropagationPolicy)
+	}
+	switch s.DeletionPolicy {
+	case "", PatchDeletionRollBack, PatchDeletionKeep:
+	default:
+		return errors.Errorf(errFmtInvalidDeletion, s.DeletionPolicy)
+	}
+	if s.Override.Metadata != nil {
+		return s.Override.Metadata.Validate(nil)
+	}
+	return nil
+}
+
+// wellKnownAPIVersions are the apiVersions DefaultTargetAPIVersion assigns to
+// the target references of the well-known kinds.
+var wellKnownAPIVersions = map[string]string{
+	"ConfigMap":                   "v1",
+	"Secret":                      "v1",
+	"Service":                     "v1",
+	"ServiceAccount":              "v1",
+	"Deployment":                  "apps/v1",
+	"StatefulSet":                 "apps/v1",
+	"DaemonSet":                   "apps/v1",
+	"Composition":                 "apiextensions.crossplane.io/v1",
+	"CompositeResourceDefinition": "apiextensions.crossplane.io/v1",
+	"Provider":                    "pkg.crossplane.io/v1",
+	"Configuration":               "pkg.crossplane.io/v1",
+}
+
+// DefaultTargetAPIVersion sets the apiVersion of the target reference if it
+// is empty and the target is of a well-known kind:
+//   - ConfigMap, Secret, Service, ServiceAccount: v1
+//   - Deployment, StatefulSet, DaemonSet: apps/v1
+//   - Composition, CompositeResourceDefinition: apiextensions.crossplane.io/v1
+//   - Provider, Configuration: pkg.crossplane.io/v1
+//
+// Targets of other kinds, such as claims and composite resources, must
+// specify their apiVersion explicitly.
+func (s *InControlPlaneOverrideSpec) DefaultTargetAPIVersion() {
+	if s.TargetRef.APIVersion != "" {
+		return
+	}
+	s.TargetRef.APIVersion = wellKnownAPIVersions[s.TargetRef.Kind]
+}
+
+// AnnotationPolicy decides which annotations can be patched with an
+// InControlPlaneOverride.
+// +kubebuilder:object:generate=false
+type AnnotationPolicy interface {
+	// Allowed returns true if the annotation with the supplied key can be
+	// patched.
+	Allowed(key string) bool
+}
+
+// AnnotationPolicyFunc is a function that satisfies the AnnotationPolicy
+// interface.
+// +kubebuilder:object:generate=false
+type AnnotationPolicyFunc func(key string) bool
+
+// Allowed calls fn.
+func (fn AnnotationPolicyFunc) Allowed(key string) bool {
+	return fn(key)
+}
+
+// DefaultAnnotationPolicy allows only the annotations that the API server
+// allows to be patched, i.e., the annotations listed in OverridableFields.
+var DefaultAnnotationPolicy AnnotationPolicy = AnnotationPolicyFunc(func(key string) bool {
+	for _, f := range OverridableFields() {
+		if f.Path == FieldPathAnnotations && f.Key == key {
+			return true
+		}
+	}
+	return false
+})
+
+// Validate checks that only the annotations allowed by the supplied policy
+// are being patched. The DefaultAnnotationPolicy is used if the policy is nil.
+func (m *MetadataPatch) Validate(p AnnotationPolicy) error {
+	if p == nil {
+		p = DefaultAnnotationPolicy
+	}
+	for k := range m.Annotations {
+		if !p.Allowed(k) {
+			return errors.Errorf(errFmtAnnotationNotAllowed, k)
+		}
+	}
+	return nil
+}
+
+// SemanticWarnings returns advisory warnings for combinations of annotations
+// that are allowed but likely contradictory, such as forcing the
+// reconciliation of objects that are also being paused, in which case the
+// paused objects are not reconciled. The warnings are not errors and the
+// patch may still be valid.
+func (m *MetadataPatch) SemanticWarnings() []string {
+	var warnings []string
+	if m.Annotations[AnnotationKeyPaused] == "true" && m.Annotations[AnnotationKeyForceReconcileAt] != "" {
+		warnings = append(warnings, fmt.Sprintf("annotation %s has no effect while annotation %s is %q: the target objects will not be reconciled", AnnotationKeyForceReconcileAt, AnnotationKeyPaused, "true"))
+	}
+	return warnings
+}
+
+// FieldPathAnnotations is the path of the annotations in an Override.
+const FieldPathAnnotations = "metadata.annotations"
+
+// FieldDescriptor describes a field that can be patched with an
+// InControlPlaneOverride.
+// +kubebuilder:object:generate=false
+type FieldDescriptor struct {
+	// Path of the field in the Override.
+	Path string
+	// Key in the map at Path, for map-typed fields whose keys are restricted.
+	Key string
+	// Type of the field's value.
+	Type string
+	// Description is a human-readable description of the field.
+	Description string
+}
+
+// OverridableFields returns the descriptors of the fields that can be patched
+// with an InControlPlaneOverride, so that clients such as UIs can discover
+// them instead of hardcoding them.
+func OverridableFields() []FieldDescriptor {
+	return []FieldDescriptor{
+		{
+			Path:        FieldPathAnnotations,
+			Key:         AnnotationKeyPaused,
+			Type:        "string",
+			Description: `Pauses the reconciliation of the target objects when set to "true".`,
+		},
+		{
+			Path:        FieldPathAnnotations,
+			Key:         AnnotationKeyForceReconcileAt,
+			Type:        "string",
+			Description: "Forces the reconciliation of the target objects at the specified time.",
+		},
+	}
+}
+
+// ValidateTargetScope checks that the namespace of the target reference is
+// consistent with the scope of the target's kind. Namespaced targets, such as
+// claims, must specify a namespace, whereas cluster-scoped targets, such as
+// composite resources, must not. Either mismatch would otherwise surface as a
+// not-found error while the override is being applied.
+// ErrTargetNamespaceRequired or ErrTargetNamespaceNotAllowed is returned
+// depending on the case.
+func (s *InControlPlaneOverrideSpec) ValidateTargetScope(namespaced bool) error {
+	ns := ptr.Deref(s.TargetRef.Namespace, "")
+	switch {
+	case namespaced && ns == "":
+		return ErrTargetNamespaceRequired
+	case !namespaced && ns != "":
+		return ErrTargetNamespaceNotAllowed
+	}
+	return nil
+}
+
+// ProtectedNamespaces are the system namespaces of a ControlPlane whose
+// objects cannot be targeted by InControlPlaneOverrides, as they host the
+// infrastructure of the ControlPlane itself.
+var ProtectedNamespaces = []string{"crossplane-system", "kube-system", "upbound-system"}
+
+// ValidateNotSystemTarget checks that the target reference does not refer to
+// a ProtectedNamespace or to an object in one.
+func (s *InControlPlaneOverrideSpec) ValidateNotSystemTarget() error {
+	ns := ptr.Deref(s.TargetRef.Namespace, "")
+	if ns == "" && s.TargetRef.APIVersion == "v1" && s.TargetRef.Kind == "Namespace" {
+		ns = s.TargetRef.Name
+	}
+	if slices.Contains(ProtectedNamespaces, ns) {
+		return errors.Errorf(errFmtSystemTarget, ns)
+	}
+	return nil
+}
+
+// DefaultMaxOverrideTargets is the maximum number of objects an
+// InControlPlaneOverride's target hierarchy may resolve to when no other
+// maximum is configured.
+const DefaultMaxOverrideTargets = 1000
+
+// ValidateTargetCount checks that the target hierarchy of an override, which
+// has been resolved to the supplied number of objects, does not exceed the
+// supplied maximum number of objects, so that an override does not
+// unexpectedly fan out to thousands of objects. DefaultMaxOverrideTargets is used if the
+// maximum is not positive. Callers that would rather truncate the hierarchy
+// than reject the override can patch only the first limit objects instead.
+func ValidateTargetCount(resolved, limit int) error {
+	if limit <= 0 {
+		limit = DefaultMaxOverrideTargets
+	}
+	if resolved > limit {
+		return errors.Errorf(errFmtTooManyTargets, resolved, limit)
+	}
+	return nil
+}
+
+// IsDryRun returns true if this InControlPlaneOverride only plans its
+// configuration override rather than applying it.
+func (o *InControlPlaneOverride) IsDryRun() bool {
+	return ptr.Deref(o.Spec.DryRun, false)
+}
+
+// ValidateDryRun checks the invariant that a dry run InControlPlaneOverride
+// never mutates the objects in its target hierarchy, i.e., that none of the
+// objects in its status have been successfully patched.
+func (o *InControlPlaneOverride) ValidateDryRun() error {
+	if !o.IsDryRun() {
+		return nil
+	}
+	if r := o.Status.firstInState(PatchStateSuccess); r != nil {
+		return errors.Errorf(errFmtDryRunPatched, objectName(r.ObjectReference))
+	}
+	return nil
+}
+
+// PropagationWarnings returns advisory warnings if the PropagationPolicy is
+// unlikely to match the kind of the target. Descending traversals follow the
+// spec.resourceRef & spec.resourceRefs fields of claims and composite
+// resources, whereas ascending traversals follow the owner references of
+// managed resources and composite resources. As the scope of the target is
+// not known, namespaced targets are assumed to be claims and cluster-scoped
+// targets whose kinds are not prefixed with "X" are assumed to be managed
+// resources, following the Crossplane naming conventions. The warnings are not
+// errors and the override may still be valid.
+func (s *InControlPlaneOverrideSpec) PropagationWarnings() []string {
+	claim := ptr.Deref(s.TargetRef.Namespace, "") != ""
+	composite := !claim && strings.HasPrefix(s.TargetRef.Kind, "X")
+	var warnings []string
+	switch s.PropagationPolicy {
+	case PatchPropagateAscending:
+		if claim {
+			warnings = append(warnings, fmt.Sprintf("target %s looks like a claim, which is not owned by other objects: propagationPolicy %q will likely only patch the target", objectName(s.TargetRef), s.PropagationPolicy))
+		}
+	case PatchPropagateDescending:
+		if !claim && !composite {
+			warnings = append(warnings, fmt.Sprintf("target %s looks like a managed resource, which does not reference other objects: propagationPolicy %q will likely only patch the target", objectName(s.TargetRef), s.PropagationPolicy))
+		}
+	case "", PatchPropagateNone:
+	}
+	return warnings
+}
+
+// PatchPending returns a PatchedObjectStatus that indicates the referenced
+// object has been queued for patching but has not been processed yet.
+func PatchPending(ref ObjectReference, uid *types.UID) PatchedObjectStatus {
+	return PatchedObjectStatus{
+		ObjectReference: ref,
+		UID:             uid,
+		Status:          PatchStatePending,
+	}
+}
+
+// PatchSuccess returns a PatchedObjectStatus that indicates the referenced
+// object has been patched with the fully specified intent of the supplied
+// hash.
+func PatchSuccess(ref ObjectReference, uid *types.UID, hash string) PatchedObjectStatus {
+	return PatchedObjectStatus{
+		ObjectReference: ref,
+		UID:             uid,
+		Status:          PatchStateSuccess,
+		PatchHash:       hash,
+	}
+}
+
+// PatchPlanned returns a PatchedObjectStatus that indicates the referenced
+// object would be patched by a dry run InControlPlaneOverride.
+func PatchPlanned(ref ObjectReference, uid *types.UID) PatchedObjectStatus {
+	return PatchedObjectStatus{
+		ObjectReference: ref,
+		UID:             uid,
+		Status:          PatchStatePlanned,
+	}
+}
+
+// PatchNoChange returns a PatchedObjectStatus that indicates patching the
+// referenced object has been skipped as it already has the desired
+// configuration.
+func PatchNoChange(ref ObjectReference, uid *types.UID) PatchedObjectStatus {
+	return PatchedObjectStatus{
+		ObjectReference: ref,
+		UID:             uid,
+		Status:          PatchStateSkipped,
+		Reason:          PatchStateReasonNoChange,
+	}
+}
+
+// Changes returns true if applying this Override would change the supplied
+// object, i.e., if any of the overridden annotations is missing from the
+// object or has a different value. An object that is already paused, for
+// example, is not changed by an Override pausing it. As the intent built by
+// ToApplyConfiguration sets the overridden annotations to their values
+// verbatim, an annotation overridden with an empty value is kept with an
+// empty value rather than being removed, and it changes the object unless
+// the object already has it with an empty value.
+func (o *Override) Changes(obj metav1.Object) bool {
+	if o.Metadata == nil {
+		return false
+	}
+	current := obj.GetAnnotations()
+	for k, v := range o.Metadata.Annotations {
+		if cv, ok := current[k]; !ok || cv != v {
+			return true
+		}
+	}
+	return false
+}
+
+// msgFieldNotDeclared is the message server-side apply reports for the fields
+// of an intent that do not exist in the schema of the target object.
+const msgFieldNotDeclared = "field not declared in schema"
+
+// PatchFailure returns a PatchedObjectStatus that classifies the supplied
+// error returned while patching the referenced object. Failures that will
+// not succeed on retry, i.e., conflicts with other field managers, schema
+// mismatches, invalid or forbidden patches and deleted objects, are reported
+// as skipped. Timeouts and any other errors, including a nil error, are
+// reported as transient errors to be retried.
+func PatchFailure(ref ObjectReference, uid *types.UID, err error) PatchedObjectStatus {
+	if err == nil {
+		return PatchedObjectStatus{
+			ObjectReference: ref,
+			UID:             uid,
+			Status:          PatchStateError,
+			Reason:          PatchStateReasonUnknown,
+		}
+	}
+	s := PatchedObjectStatus{
+		ObjectReference: ref,
+		UID:             uid,
+		Status:          PatchStateSkipped,
+		Message:         ptr.To(err.Error()),
+	}
+	switch {
+	case apierrors.IsConflict(err):
+		s.Reason = PatchStateReasonConflict
+	case strings.Contains(err.Error(), msgFieldNotDeclared):
+		s.Reason = PatchStateReasonSchemaMismatch
+	case apierrors.IsInvalid(err):
+		s.Reason = PatchStateReasonInvalid
+	case apierrors.IsForbidden(err):
+		s.Reason = PatchStateReasonForbidden
+	case apierrors.IsNotFound(err):
+		s.Reason = PatchStateReasonNotFound
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		s.Status = PatchStateError
+		s.Reason = PatchStateReasonTimeout
+	default:
+		s.Status = PatchStateError
+		s.Reason = PatchStateReasonUnknown
+	}
+	return s
+}
+
+// Retryable returns true if patching the object has failed with a transient
+// error and should be retried with a backoff.
+func (r *PatchedObjectStatus) Retryable() bool {
+	return r.Status == PatchStateError
+}
+
+// patchStateTransitions is the graph of the allowed PatchState transitions of
+// a patched object, keyed by the old state. An object is queued as Pending and
+// is then patched successfully, skipped or fails with an Error. Errors are
+// retried, either directly or by queueing the object again. A Success or a
+// Skipped object is never queued again, but it can be patched again, e.g.,
+// when the override changes, and move to any of Success, Skipped or Error.
+// Staying in the same state is always allowed. A dry run reports queued or
+// failed objects as Planned instead of patching them. Objects that have been
+// patched or skipped are never planned again, while Planned objects can be
+// queued or patched once the dry run is turned off.
+var patchStateTransitions = map[PatchState][]PatchState{
+	PatchStatePending: {PatchStatePending, PatchStateSuccess, PatchStateSkipped, PatchStateError, PatchStatePlanned},
+	PatchStateError:   {PatchStatePending, PatchStateSuccess, PatchStateSkipped, PatchStateError, PatchStatePlanned},
+	PatchStateSuccess: {PatchStateSuccess, PatchStateSkipped, PatchStateError},
+	PatchStateSkipped: {PatchStateSuccess, PatchStateSkipped, PatchStateError},
+	PatchStatePlanned: {PatchStatePending, PatchStateSuccess, PatchStateSkipped, PatchStateError, PatchStatePlanned},
+}
+
+// ValidatePatchStateTransition returns an error if a patched object is not
+// allowed to move from one PatchState to another. An empty from state denotes
+// an object that is newly added to the status, which may start in any state.
+func ValidatePatchStateTransition(from, to PatchState) error {
+	if _, ok := patchStateTransitions[to]; !ok {
+		return errors.Errorf(errFmtInvalidPatchState, to)
+	}
+	if from == "" {
+		return nil
+	}
+	allowed, ok := patchStateTransitions[from]
+	if !ok {
+		return errors.Errorf(errFmtInvalidPatchState, from)
+	}
+	if !slices.Contains(allowed, to) {
+		return errors.Errorf(errFmtPatchStateTransition, from, to)
+	}
+	return nil
+}
+
+// PatchSummary counts the objects in an InControlPlaneOverride's status by
+// their PatchState.
+// +kubebuilder:object:generate=false
+type PatchSummary struct {
+	Success int
+	Pending int
+	Skipped int
+	Error   int
+	Planned int
+}
+
+// Summary returns the number of objects in each PatchState.
+func (s *InControlPlaneOverrideStatus) Summary() PatchSummary {
+	var sum PatchSummary
+	for _, r := range s.ObjectRefs {
+		switch r.Status {
+		case PatchStateSuccess:
+			sum.Success++
+		case PatchStatePending:
+			sum.Pending++
+		case PatchStateSkipped:
+			sum.Skipped++
+		case PatchStateError:
+			sum.Error++
+		case PatchStatePlanned:
+			sum.Planned++
+		}
+	}
+	return sum
+}
+
+// ObjectRefTransition represents a change in the PatchState of an object in
+// an InControlPlaneOverride's status.
+// +kubebuilder:object:generate=false
+type ObjectRefTransition struct {
+	// Object is the status of the object after the transition.
+	Object PatchedObjectStatus
+	// Old is the PatchState of the object before the transition. It is empty
+	// if the object was not previously reported.
+	Old PatchState
+	// New is the PatchState of the object after the transition.
+	New PatchState
+}
+
+// Validate checks the invariant that each object appears at most once in
+// the ObjectRefs of this status, i.e., that there are neither duplicate
+// object references nor duplicate UIDs. It is a defensive check against bugs
+// in the status bookkeeping, to be used in tests and optionally at runtime.
+func (s *InControlPlaneOverrideStatus) Validate() error {
+	refs := make(map[string]struct{}, len(s.ObjectRefs))
+	uids := make(map[types.UID]ObjectReference, len(s.ObjectRefs))
+	for _, r := range s.ObjectRefs {
+		k := objectKey(r.ObjectReference)
+		if _, ok := refs[k]; ok {
+			return errors.Errorf(errFmtDuplicateObjectRef, objectName(r.ObjectReference))
+		}
+		refs[k] = struct{}{}
+		if r.UID == nil || *r.UID == "" {
+			continue
+		}
+		if other, ok := uids[*r.UID]; ok {
+			return errors.Errorf(errFmtDuplicateUID, *r.UID, objectName(other), objectName(r.ObjectReference))
+		}
+		uids[*r.UID] = r.ObjectReference
+	}
+	return nil
+}
+
+// DiffObjectRefs returns the objects in newRefs whose PatchState differs from
+// the one reported for them in oldRefs, including the objects that were not
+// previously reported. Objects are matched by their UIDs if both have one,
+// and by their apiVersion, kind, namespace and name only if either has no
+// UID. An object whose UID differs from the one previously reported for the
+// same reference has been recreated and is reported as not previously
+// reported. The returned transitions are in the order of newRefs.
+func DiffObjectRefs(oldRefs, newRefs []PatchedObjectStatus) []ObjectRefTransition {
+	idx := newObjectIndex(oldRefs)
+	var transitions []ObjectRefTransition
+	for _, r := range newRefs {
+		old, _ := idx.lookup(r)
+		if old.Status == r.Status {
+			continue
+		}
+		transitions = append(transitions, ObjectRefTransition{
+			Object: r,
+			Old:    old.Status,
+			New:    r.Status,
+		})
+	}
+	return transitions
+}
+
+// objectIndex indexes previously reported object statuses by their UIDs and
+// by their references.
+type objectIndex struct {
+	byUID map[types.UID]PatchedObjectStatus
+	byKey map[string]PatchedObjectStatus
+}
+
+func newObjectIndex(refs []PatchedObjectStatus) objectIndex {
+	idx := objectIndex{
+		byUID: make(map[types.UID]PatchedObjectStatus, len(refs)),
+		byKey: make(map[string]PatchedObjectStatus, len(refs)),
+	}
+	for _, r := range refs {
+		if r.UID != nil {
+			idx.byUID[*r.UID] = r
+		}
+		idx.byKey[objectKey(r.ObjectReference)] = r
+	}
+	return idx
+}
+
+// lookup returns the status previously reported for the object of the
+// supplied status. Objects are matched by their UIDs if both have one, and by
+// their references only if either has no UID.
+func (idx objectIndex) lookup(r PatchedObjectStatus) (PatchedObjectStatus, bool) {
+	if r.UID != nil {
+		if o, ok := idx.byUID[*r.UID]; ok {
+			return o, true
+		}
+	}
+	if o, ok := idx.byKey[objectKey(r.ObjectReference)]; ok && (r.UID == nil || o.UID == nil) {
+		return o, true
+	}
+	return PatchedObjectStatus{}, false
+}
+
+// objectKey returns a key identifying the referenced object by its
+// apiVersion, kind, namespace and name.
+func objectKey(r ObjectReference) string {
+	return strings.Join([]string{r.APIVersion, r.Kind, ptr.Deref(r.Namespace, ""), r.Name}, "/")
+}
+
+// PruneObjectRefs returns the object statuses in refs whose objects are still
+// in the current hierarchy of an InControlPlaneOverride's target. As typed
+// object references do not carry a UID or a version, objects are matched by
+// their API group, kind, namespace and name.
+func PruneObjectRefs(refs []PatchedObjectStatus, current []corev1.TypedObjectReference) []PatchedObjectStatus {
+	present := make(map[string]struct{}, len(current))
+	for _, c := range current {
+		present[groupKindKey(ptr.Deref(c.APIGroup, ""), c.Kind, ptr.Deref(c.Namespace, ""), c.Name)] = struct{}{}
+	}
+	pruned := make([]PatchedObjectStatus, 0, len(refs))
+	for _, r := range refs {
+		// an unparsable apiVersion yields an empty group, which will not
+		// match any non-core object in the current hierarchy.
+		gv, _ := schema.ParseGroupVersion(r.APIVersion)
+		if _, ok := present[groupKindKey(gv.Group, r.Kind, ptr.Deref(r.Namespace, ""), r.Name)]; ok {
+			pruned = append(pruned, r)
+		}
+	}
+	return pruned
+}
+
+func groupKindKey(group, kind, namespace, name string) string {
+	return strings.Join([]string{group, kind, namespace, name}, "/")
+}
+
+// NewInControlPlaneOverride returns an InControlPlaneOverride that applies the
+// supplied override to the target object in the named ControlPlane. Its name
+// is generated by the API server with the ControlPlane's name as prefix, and
+// it uses the default propagation and deletion policies. The namespace of the
+// returned object, which must be the ControlPlane's namespace, is left for the
+// caller to set.
+func NewInControlPlaneOverride(ctpName string, target ObjectReference, override Override) *InControlPlaneOverride {
+	return &InControlPlaneOverride{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       InControlPlaneOverrideKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: ctpName + "-",
+		},
+		Spec: InControlPlaneOverrideSpec{
+			ControlPlaneName:  ctpName,
+			TargetRef:         target,
+			PropagationPolicy: PatchPropagateNone,
+			DeletionPolicy:    PatchDeletionRollBack,
+			Override:          override,
+		},
+	}
+}
+
+// NewPauseOverride returns an InControlPlaneOverride that pauses the target
+// object in the named ControlPlane by setting its AnnotationKeyPaused
+// annotation to "true". If descend is true, the objects referenced by the
+// target, such as the composite resource of a claim and its composed
+// resources, are also paused.
+func NewPauseOverride(ctpName string, target ObjectReference, descend bool) *InControlPlaneOverride {
+	o := NewInControlPlaneOverride(ctpName, target, Override{
+		Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: "true"}},
+	})
+	if descend {
+		o.Spec.PropagationPolicy = PatchPropagateDescending
+	}
+	return o
+}
+
+// NewUnpauseOverride returns an InControlPlaneOverride that reverses a pause
+// of the target object in the named ControlPlane by overriding its
+// AnnotationKeyPaused annotation with an empty value. The annotation is not
+// removed but kept with the empty value, which unpauses the objects as
+// Crossplane only pauses objects whose annotation is "true". If descend is
+// true, the objects referenced by the target are also unpaused.
+func NewUnpauseOverride(ctpName string, target ObjectReference, descend bool) *InControlPlaneOverride {
+	o := NewInControlPlaneOverride(ctpName, target, Override{
+		Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: ""}},
+	})
+	if descend {
+		o.Spec.PropagationPolicy = PatchPropagateDescending
+	}
+	return o
+}
+
+const (
+	// fieldManagerPrefix is the prefix of the server-side apply field
+	// managers of InControlPlaneOverrides.
+	fieldManagerPrefix = "spaces.upbound.io/override/"
+	// maxFieldManagerLength is the maximum length of a field manager name
+	// accepted by the API server.
+	maxFieldManagerLength = 128
+)
+
+// FieldManager returns the server-side apply field manager to be used when
+// applying this InControlPlaneOverride, so that re-applies do not conflict
+// with earlier applies of the same override and conflicts with other
+// managers can be attributed to it. The name has the form
+// "spaces.upbound.io/override/<namespace>/<name>". Names exceeding the
+// maximum field manager length of 128 characters are truncated, and a hash of
+// the namespace and name is appended to keep them unique.
+func (o *InControlPlaneOverride) FieldManager() string {
+	m := fieldManagerPrefix + o.GetNamespace() + "/" + o.GetName()
+	if len(m) <= maxFieldManagerLength {
+		return m
+	}
+	sum := sha256.Sum256([]byte(o.GetNamespace() + "/" + o.GetName()))
+	h := hex.EncodeToString(sum[:])[:16]
+	return m[:maxFieldManagerLength-len(h)-1] + "-" + h
+}
+
+// Key returns a stable key identifying the target of this
+// InControlPlaneOverride, combining the namespace of the override, the name
+// of its ControlPlane and the apiVersion, kind, namespace and name of its
+// target object. Overrides of the same target have the same key regardless of
+// their names and propagation policies, so that duplicates can be coalesced.
+func (o *InControlPlaneOverride) Key() string {
+	return o.GetNamespace() + "/" + o.Spec.ControlPlaneName + "/" + objectKey(o.Spec.TargetRef)
+}
+
+// Overlaps returns true if the supplied InControlPlaneOverrides could patch
+// the same objects. Overrides in different namespaces or targeting different
+// ControlPlanes never overlap. Overrides that do not propagate overlap only if
+// they have the same target. As the hierarchies of the targets are not known
+// statically, overrides that propagate to a target's owners or descendants
+// are conservatively reported to overlap with any other override in the same
+// ControlPlane, so Overlaps may over-report for these propagation policies.
+func Overlaps(a, b *InControlPlaneOverride) bool {
+	if a.GetNamespace() != b.GetNamespace() || a.Spec.ControlPlaneName != b.Spec.ControlPlaneName {
+		return false
+	}
+	if objectKey(a.Spec.TargetRef) == objectKey(b.Spec.TargetRef) {
+		return true
+	}
+	return propagates(a.Spec.PropagationPolicy) || propagates(b.Spec.PropagationPolicy)
+}
+
+func propagates(p PatchPropagationPolicy) bool {
+	return p == PatchPropagateAscending || p == PatchPropagateDescending
+}
+
+// maxEventMessageLength is the maximum length of the messages returned by
+// EventMessage. It is well below the 1 KiB limit the API server imposes on
+// event notes.
+const maxEventMessageLength = 512
+
+// EventMessage returns a single-line summary of the status suitable for an
+// event message. It reports the number of objects in each PatchState, and the
+// first object that failed to be patched and the first object that was
+// skipped, if any. Messages longer than 512 bytes are truncated on a rune
+// boundary, so that they remain valid UTF-8.
+func (s *InControlPlaneOverrideStatus) EventMessage() string {
+	sum := s.Summary()
+	msg := fmt.Sprintf("%d succeeded, %d planned, %d pending, %d skipped, %d failed", sum.Success, sum.Planned, sum.Pending, sum.Skipped, sum.Error)
+	if r := s.firstInState(PatchStateError); r != nil {
+		msg += fmt.Sprintf("; first error: %s: %s", objectName(r.ObjectReference), ptr.Deref(r.Message, "unknown error"))
+	}
+	if r := s.firstInState(PatchStateSkipped); r != nil {
+		msg += fmt.Sprintf("; first skipped: %s: %s", objectName(r.ObjectReference), r.Reason)
+	}
+	msg = strings.Join(strings.Fields(msg), " ")
+	if len(msg) > maxEventMessageLength {
+		i := maxEventMessageLength - 3
+		for i > 0 && !utf8.RuneStart(msg[i]) {
+			i--
+		}
+		msg = msg[:i] + "..."
+	}
+	return msg
+}
+
+func (s *InControlPlaneOverrideStatus) firstInState(state PatchState) *PatchedObjectStatus {
+	for i := range s.ObjectRefs {
+		if s.ObjectRefs[i].Status == state {
+			return &s.ObjectRefs[i]
+		}
+	}
+	return nil
+}
+
+// objectName returns a short, human-readable name of the referenced object in
+// the form of <kind>/[<namespace>/]<name>.
+func objectName(r ObjectReference) string {
+	if ns := ptr.Deref(r.Namespace, ""); ns != "" {
+		return r.Kind + "/" + ns + "/" + r.Name
+	}
+	return r.Kind + "/" + r.Name
+}
+
+// Hash returns a hex-encoded SHA-256 hash of the fully specified intent
+// obtained by serializing this Override. Equal overrides hash to the same
+// value as the serialized map keys are sorted.
+func (o *Override) Hash() (string, error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return "", errors.Wrap(err, errHashOverride)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// IsUpToDate returns true if the intent with the supplied hash has already
+// been successfully applied to the object, in which case it need not be
+// re-applied.
+func (r *PatchedObjectStatus) IsUpToDate(hash string) bool {
+	return r.Status == PatchStateSuccess && r.PatchHash != "" && r.PatchHash == hash
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+// SimulateOverride returns the references to the objects in the supplied
+// object set that an InControlPlaneOverride with the given spec would patch,
+// in traversal order. Objects in the set that are not returned would be
+// skipped. The hierarchy of the target object is resolved from the supplied
+// objects only, honoring the spec's PropagationPolicy: ascending traversals
+// follow the metadata.ownerReferences and descending traversals follow the
+// spec.resourceRef & spec.resourceRefs fields. SimulateOverride returns nil
+// if the target object is not in the set.
+func SimulateOverride(spec InControlPlaneOverrideSpec, objects []*unstructured.Unstructured) []ObjectReference {
+	g := newObjectGraph(objects)
+	target, ok := g.byKey[objectKey(spec.TargetRef)]
+	if !ok {
+		return nil
+	}
+
+	var patched []ObjectReference
+	visited := make(map[string]struct{}, len(objects))
+	queue := []*unstructured.Unstructured{target}
+	for len(queue) > 0 {
+		o := queue[0]
+		queue = queue[1:]
+		ref := objectRefOf(o)
+		if _, ok := visited[objectKey(ref)]; ok {
+			continue
+		}
+		visited[objectKey(ref)] = struct{}{}
+		patched = append(patched, ref)
+
+		switch spec.PropagationPolicy {
+		case PatchPropagateAscending:
+			queue = append(queue, g.owners(o)...)
+		case PatchPropagateDescending:
+			queue = append(queue, g.referenced(o)...)
+		case PatchPropagateNone:
+		}
+	}
+	return patched
+}
+
+// objectGraph indexes an in-memory object set for hierarchy traversals.
+type objectGraph struct {
+	byKey map[string]*unstructured.Unstructured
+	byUID map[types.UID]*unstructured.Unstructured
+}
+
+func newObjectGraph(objects []*unstructured.Unstructured) *objectGraph {
+	g := &objectGraph{
+		byKey: make(map[string]*unstructured.Unstructured, len(objects)),
+		byUID: make(map[types.UID]*unstructured.Unstructured, len(objects)),
+	}
+	for _, o := range objects {
+		if o == nil {
+			continue
+		}
+		g.byKey[objectKey(objectRefOf(o))] = o
+		if uid := o.GetUID(); uid != "" {
+			g.byUID[uid] = o
+		}
+	}
+	return g
+}
+
+// owners returns the objects in the graph that own the supplied object.
+// Owners are matched by their UID, or by their apiVersion, kind and name if
+// the UID is not in the graph. An owner is either in the namespace of the
+// owned object or cluster-scoped.
+func (g *objectGraph) owners(o *unstructured.Unstructured) []*unstructured.Unstructured {
+	var owners []*unstructured.Unstructured
+	for _, or := range o.GetOwnerReferences() {
+		if owner, ok := g.byUID[or.UID]; ok {
+			owners = append(owners, owner)
+			continue
+		}
+		if owner := g.lookup(or.APIVersion, or.Kind, or.Name, "", o.GetNamespace()); owner != nil {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// referenced returns the objects in the graph that are referenced by the
+// supplied object's spec.resourceRef & spec.resourceRefs fields. A reference
+// without a namespace resolves to either a cluster-scoped object or an object
+// in the namespace of the referencing object.
+func (g *objectGraph) referenced(o *unstructured.Unstructured) []*unstructured.Unstructured {
+	var refs []map[string]any
+	if ref, ok, _ := unstructured.NestedMap(o.Object, "spec", "resourceRef"); ok {
+		refs = append(refs, ref)
+	}
+	if list, ok, _ := unstructured.NestedSlice(o.Object, "spec", "resourceRefs"); ok {
+		for _, r := range list {
+			if ref, ok := r.(map[string]any); ok {
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	var children []*unstructured.Unstructured
+	for _, ref := range refs {
+		apiVersion, _, _ := unstructured.NestedString(ref, "apiVersion")
+		kind, _, _ := unstructured.NestedString(ref, "kind")
+		name, _, _ := unstructured.NestedString(ref, "name")
+		namespace, _, _ := unstructured.NestedString(ref, "namespace")
+		if child := g.lookup(apiVersion, kind, name, namespace, o.GetNamespace()); child != nil {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// lookup returns the object with the supplied apiVersion, kind, name and
+// namespace. If the namespace is empty, a cluster-scoped object is preferred
+// over one in the fallback namespace.
+func (g *objectGraph) lookup(apiVersion, kind, name, namespace, fallback string) *unstructured.Unstructured {
+	ref := ObjectReference{APIVersion: apiVersion, Kind: kind, Name: name}
+	if namespace != "" {
+		ref.Namespace = ptr.To(namespace)
+		return g.byKey[objectKey(ref)]
+	}
+	if o, ok := g.byKey[objectKey(ref)]; ok {
+		return o
+	}
+	ref.Namespace = ptr.To(fallback)
+	return g.byKey[objectKey(ref)]
+}
+
+// objectRefOf returns an ObjectReference to the supplied object.
+func objectRefOf(o *unstructured.Unstructured) ObjectReference {
+	ref := ObjectReference{
+		APIVersion: o.GetAPIVersion(),
+		Kind:       o.GetKind(),
+		Name:       o.GetName(),
+	}
+	if ns := o.GetNamespace(); ns != "" {
+		ref.Namespace = ptr.To(ns)
+	}
+	return ref
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+func TestSimulateOverride(t *testing.T) {
+	claim := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1alpha1",
+		"kind":       "Network",
+		"metadata":   map[string]any{"name": "network", "namespace": "default", "uid": "claim-uid"},
+		"spec": map[string]any{
+			"resourceRef": map[string]any{"apiVersion": "example.org/v1alpha1", "kind": "XNetwork", "name": "network-abcde"},
+		},
+	}}
+	xr := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1alpha1",
+		"kind":       "XNetwork",
+		"metadata":   map[string]any{"name": "network-abcde", "uid": "xr-uid"},
+		"spec": map[string]any{
+			"resourceRefs": []any{
+				map[string]any{"apiVersion": "ec2.aws.upbound.io/v1beta1", "kind": "VPC", "name": "vpc"},
+				map[string]any{"apiVersion": "ec2.aws.upbound.io/v1beta1", "kind": "Subnet", "name": "subnet"},
+				map[string]any{"apiVersion": "ec2.aws.upbound.io/v1beta1", "kind": "Subnet", "name": "missing"},
+			},
+		},
+	}}
+	vpc := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind":       "VPC",
+		"metadata": map[string]any{
+			"name": "vpc",
+			"uid":  "vpc-uid",
+			"ownerReferences": []any{
+				map[string]any{"apiVersion": "example.org/v1alpha1", "kind": "XNetwork", "name": "network-abcde", "uid": "xr-uid"},
+			},
+		},
+	}}
+	subnet := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "ec2.aws.upbound.io/v1beta1",
+		"kind":       "Subnet",
+		"metadata": map[string]any{
+			"name": "subnet",
+			"ownerReferences": []any{
+				map[string]any{"apiVersion": "example.org/v1alpha1", "kind": "XNetwork", "name": "network-abcde", "uid": "unknown-uid"},
+			},
+		},
+	}}
+	objects := []*unstructured.Unstructured{claim, xr, vpc, subnet}
+
+	claimRef := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+	xrRef := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network-abcde"}
+	vpcRef := ObjectReference{APIVersion: "ec2.aws.upbound.io/v1beta1", Kind: "VPC", Name: "vpc"}
+	subnetRef := ObjectReference{APIVersion: "ec2.aws.upbound.io/v1beta1", Kind: "Subnet", Name: "subnet"}
+
+	cases := map[string]struct {
+		reason  string
+		spec    InControlPlaneOverrideSpec
+		objects []*unstructured.Unstructured
+		want    []ObjectReference
+	}{
+		"TargetNotFound": {
+			reason:  "No objects should be patched if the target is not in the object set.",
+			spec:    InControlPlaneOverrideSpec{TargetRef: claimRef, PropagationPolicy: PatchPropagateDescending},
+			objects: []*unstructured.Unstructured{xr, vpc, subnet},
+		},
+		"None": {
+			reason:  "Only the target should be patched if the override does not propagate.",
+			spec:    InControlPlaneOverrideSpec{TargetRef: xrRef, PropagationPolicy: PatchPropagateNone},
+			objects: objects,
+			want:    []ObjectReference{xrRef},
+		},
+		"Descending": {
+			reason:  "The target and the objects it references, directly or transitively, should be patched in traversal order.",
+			spec:    InControlPlaneOverrideSpec{TargetRef: claimRef, PropagationPolicy: PatchPropagateDescending},
+			objects: objects,
+			want:    []ObjectReference{claimRef, xrRef, vpcRef, subnetRef},
+		},
+		"AscendingByUID": {
+			reason:  "The target and its owners matched by UID should be patched.",
+			spec:    InControlPlaneOverrideSpec{TargetRef: vpcRef, PropagationPolicy: PatchPropagateAscending},
+			objects: objects,
+			want:    []ObjectReference{vpcRef, xrRef},
+		},
+		"AscendingByName": {
+			reason:  "Owners should be matched by their apiVersion, kind and name if their UID is not in the object set.",
+			spec:    InControlPlaneOverrideSpec{TargetRef: subnetRef, PropagationPolicy: PatchPropagateAscending},
+			objects: objects,
+			want:    []ObjectReference{subnetRef, xrRef},
+		},
+		"Cycle": {
+			reason: "Each object should be patched at most once even if the object graph has cycles.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: xrRef, PropagationPolicy: PatchPropagateDescending},
+			objects: []*unstructured.Unstructured{
+				{Object: map[string]any{
+					"apiVersion": "example.org/v1alpha1",
+					"kind":       "XNetwork",
+					"metadata":   map[string]any{"name": "network-abcde"},
+					"spec": map[string]any{
+						"resourceRef": map[string]any{"apiVersion": "example.org/v1alpha1", "kind": "XNetwork", "name": "network-abcde"},
+					},
+				}},
+			},
+			want: []ObjectReference{xrRef},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SimulateOverride(tc.spec, tc.objects)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSimulateOverride(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,1463 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestInControlPlaneOverrideSpecValidate(t *testing.T) {
+	target := ObjectReference{
+		APIVersion: "example.org/v1alpha1",
+		Kind:       "XNetwork",
+		Name:       "network",
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   InControlPlaneOverrideSpec
+		opts   []ValidateOption
+		want   error
+	}{
+		"Valid": {
+			reason: "A spec with a control plane name and a target reference should be valid.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName: "ctp",
+				TargetRef:        target,
+			},
+		},
+		"UnknownScope": {
+			reason: "The namespace of the target reference should not be checked if the scope of the target is not supplied.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName: "ctp",
+				TargetRef:        ObjectReference{APIVersion: target.APIVersion, Kind: target.Kind, Name: target.Name, Namespace: ptr.To("default")},
+			},
+		},
+		"ValidScope": {
+			reason: "A cluster-scoped target without a namespace should be valid.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName: "ctp",
+				TargetRef:        target,
+			},
+			opts: []ValidateOption{WithTargetScope(false)},
+		},
+		"NamespaceRequired": {
+			reason: "A namespaced target without a namespace should be invalid if the scope of the target is supplied.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName: "ctp",
+				TargetRef:        target,
+			},
+			opts: []ValidateOption{WithTargetScope(true)},
+			want: ErrTargetNamespaceRequired,
+		},
+		"NamespaceNotAllowed": {
+			reason: "A cluster-scoped target with a namespace should be invalid if the scope of the target is supplied.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName: "ctp",
+				TargetRef:        ObjectReference{APIVersion: target.APIVersion, Kind: target.Kind, Name: target.Name, Namespace: ptr.To("default")},
+			},
+			opts: []ValidateOption{WithTargetScope(false)},
+			want: ErrTargetNamespaceNotAllowed,
+		},
+		"EmptyControlPlaneName": {
+			reason: "A spec without a control plane name should be invalid.",
+			spec: InControlPlaneOverrideSpec{
+				TargetRef: target,
+			},
+			want: errors.New(errEmptyControlPlaneName),
+		},
+		"EmptyTargetKind": {
+			reason: "A spec with a target reference missing its kind should be invalid.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName: "ctp",
+				TargetRef: ObjectReference{
+					APIVersion: "example.org/v1alpha1",
+					Name:       "network",
+				},
+			},
+			want: errors.New(errEmptyTargetKind),
+		},
+		"InvalidPropagationPolicy": {
+			reason: "A spec with an unknown propagation policy should be invalid.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName:  "ctp",
+				TargetRef:         target,
+				PropagationPolicy: "Sideways",
+			},
+			want: errors.Errorf(errFmtInvalidPropagation, "Sideways"),
+		},
+		"InvalidDeletionPolicy": {
+			reason: "A spec with an unknown deletion policy should be invalid.",
+			spec: InControlPlaneOverrideSpec{
+				ControlPlaneName: "ctp",
+				TargetRef:        target,
+				DeletionPolicy:   "Orphan",
+			},
+			want: errors.Errorf(errFmtInvalidDeletion, "Orphan"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.spec.Validate(tc.opts...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultTargetAPIVersion(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		target ObjectReference
+		want   string
+	}{
+		"CoreKind": {
+			reason: "A core kind should default to the core group.",
+			target: ObjectReference{Kind: "ConfigMap", Name: "cm"},
+			want:   "v1",
+		},
+		"AppsKind": {
+			reason: "An apps kind should default to the apps group.",
+			target: ObjectReference{Kind: "Deployment", Name: "crossplane"},
+			want:   "apps/v1",
+		},
+		"CrossplaneKind": {
+			reason: "A Crossplane kind should default to its Crossplane group.",
+			target: ObjectReference{Kind: "Composition", Name: "network"},
+			want:   "apiextensions.crossplane.io/v1",
+		},
+		"UnknownKind": {
+			reason: "A kind that is not well known should not be defaulted.",
+			target: ObjectReference{Kind: "XNetwork", Name: "network"},
+		},
+		"Explicit": {
+			reason: "An explicit apiVersion should not be overwritten.",
+			target: ObjectReference{APIVersion: "example.org/v1", Kind: "ConfigMap", Name: "cm"},
+			want:   "example.org/v1",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &InControlPlaneOverrideSpec{TargetRef: tc.target}
+			s.DefaultTargetAPIVersion()
+			if diff := cmp.Diff(tc.want, s.TargetRef.APIVersion); diff != "" {
+				t.Errorf("\n%s\nDefaultTargetAPIVersion(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateTargetScope(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		namespace  *string
+		namespaced bool
+		want       error
+	}{
+		"ClaimWithNamespace": {
+			reason:     "A namespaced target with a namespace should be valid.",
+			namespace:  ptr.To("default"),
+			namespaced: true,
+		},
+		"ClaimWithoutNamespace": {
+			reason:     "A namespaced target without a namespace should be invalid.",
+			namespaced: true,
+			want:       ErrTargetNamespaceRequired,
+		},
+		"ClaimWithEmptyNamespace": {
+			reason:     "A namespaced target with an empty namespace should be invalid.",
+			namespace:  ptr.To(""),
+			namespaced: true,
+			want:       ErrTargetNamespaceRequired,
+		},
+		"CompositeWithoutNamespace": {
+			reason: "A cluster-scoped target without a namespace should be valid.",
+		},
+		"CompositeWithNamespace": {
+			reason:    "A cluster-scoped target with a namespace should be invalid.",
+			namespace: ptr.To("default"),
+			want:      ErrTargetNamespaceNotAllowed,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &InControlPlaneOverrideSpec{
+				TargetRef: ObjectReference{Namespace: tc.namespace},
+			}
+			err := s.ValidateTargetScope(tc.namespaced)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateTargetScope(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateNotSystemTarget(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		target ObjectReference
+		want   error
+	}{
+		"UserNamespace": {
+			reason: "A target in a user namespace should be valid.",
+			target: ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")},
+		},
+		"ClusterScoped": {
+			reason: "A cluster-scoped target should be valid.",
+			target: ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"},
+		},
+		"SystemNamespace": {
+			reason: "A target in a system namespace should be invalid.",
+			target: ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "crossplane", Namespace: ptr.To("crossplane-system")},
+			want:   errors.Errorf(errFmtSystemTarget, "crossplane-system"),
+		},
+		"SystemNamespaceObject": {
+			reason: "A system namespace itself should be an invalid target.",
+			target: ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: "kube-system"},
+			want:   errors.Errorf(errFmtSystemTarget, "kube-system"),
+		},
+		"UserNamespaceObject": {
+			reason: "A user namespace itself should be a valid target.",
+			target: ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: "default"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &InControlPlaneOverrideSpec{TargetRef: tc.target}
+			err := s.ValidateNotSystemTarget()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateNotSystemTarget(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateTargetCount(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		resolved int
+		limit    int
+		want     error
+	}{
+		"BelowLimit": {
+			reason:   "A hierarchy with fewer objects than the maximum should be valid.",
+			resolved: 9,
+			limit:    10,
+		},
+		"AtLimit": {
+			reason:   "A hierarchy with exactly the maximum number of objects should be valid.",
+			resolved: 10,
+			limit:    10,
+		},
+		"OverLimit": {
+			reason:   "A hierarchy with more objects than the maximum should be rejected with the count.",
+			resolved: 11,
+			limit:    10,
+			want:     errors.Errorf(errFmtTooManyTargets, 11, 10),
+		},
+		"DefaultLimit": {
+			reason:   "The default maximum should be used if no maximum is configured.",
+			resolved: DefaultMaxOverrideTargets,
+		},
+		"OverDefaultLimit": {
+			reason:   "A hierarchy exceeding the default maximum should be rejected if no maximum is configured.",
+			resolved: DefaultMaxOverrideTargets + 1,
+			want:     errors.Errorf(errFmtTooManyTargets, DefaultMaxOverrideTargets+1, DefaultMaxOverrideTargets),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateTargetCount(tc.resolved, tc.limit)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateTargetCount(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPropagationWarnings(t *testing.T) {
+	claim := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+	composite := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network-abcde"}
+	managed := ObjectReference{APIVersion: "ec2.aws.upbound.io/v1beta1", Kind: "VPC", Name: "vpc"}
+
+	cases := map[string]struct {
+		reason string
+		spec   InControlPlaneOverrideSpec
+		want   []string
+	}{
+		"None": {
+			reason: "An override that does not propagate should not produce warnings.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: claim, PropagationPolicy: PatchPropagateNone},
+		},
+		"DescendingClaim": {
+			reason: "Descending from a claim should not produce warnings.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: claim, PropagationPolicy: PatchPropagateDescending},
+		},
+		"DescendingComposite": {
+			reason: "Descending from a composite resource should not produce warnings.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: composite, PropagationPolicy: PatchPropagateDescending},
+		},
+		"DescendingManaged": {
+			reason: "Descending from a managed resource should produce a warning.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: managed, PropagationPolicy: PatchPropagateDescending},
+			want:   []string{`target VPC/vpc looks like a managed resource, which does not reference other objects: propagationPolicy "Descending" will likely only patch the target`},
+		},
+		"AscendingManaged": {
+			reason: "Ascending from a managed resource should not produce warnings.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: managed, PropagationPolicy: PatchPropagateAscending},
+		},
+		"AscendingComposite": {
+			reason: "Ascending from a composite resource should not produce warnings.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: composite, PropagationPolicy: PatchPropagateAscending},
+		},
+		"AscendingClaim": {
+			reason: "Ascending from a claim should produce a warning.",
+			spec:   InControlPlaneOverrideSpec{TargetRef: claim, PropagationPolicy: PatchPropagateAscending},
+			want:   []string{`target Network/default/network looks like a claim, which is not owned by other objects: propagationPolicy "Ascending" will likely only patch the target`},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.spec.PropagationWarnings()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPropagationWarnings(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStatusSummary(t *testing.T) {
+	ref := ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "cm"}
+
+	cases := map[string]struct {
+		reason string
+		status InControlPlaneOverrideStatus
+		want   PatchSummary
+	}{
+		"Empty": {
+			reason: "An empty status should have all counts zeroed.",
+		},
+		"Mixed": {
+			reason: "Pending objects should be counted separately from the other states.",
+			status: InControlPlaneOverrideStatus{
+				ObjectRefs: []PatchedObjectStatus{
+					PatchPending(ref, nil),
+					PatchPending(ref, nil),
+					{ObjectReference: ref, Status: PatchStateSuccess},
+					{ObjectReference: ref, Status: PatchStateSkipped, Reason: PatchStateReasonConflict},
+					{ObjectReference: ref, Status: PatchStateError},
+				},
+			},
+			want: PatchSummary{Success: 1, Pending: 2, Skipped: 1, Error: 1},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.status.Summary()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSummary(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInControlPlaneOverrideStatusValidate(t *testing.T) {
+	network := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+	subnet := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Subnet", Name: "subnet", Namespace: ptr.To("default")}
+	otherSubnet := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Subnet", Name: "subnet", Namespace: ptr.To("other")}
+
+	cases := map[string]struct {
+		reason string
+		refs   []PatchedObjectStatus
+		want   error
+	}{
+		"Empty": {
+			reason: "A status without objects should be valid.",
+		},
+		"Unique": {
+			reason: "A status with distinct objects should be valid.",
+			refs: []PatchedObjectStatus{
+				PatchSuccess(network, ptr.To(types.UID("1")), "hash"),
+				PatchPending(subnet, ptr.To(types.UID("2"))),
+				PatchPending(otherSubnet, nil),
+			},
+		},
+		"UnknownUIDs": {
+			reason: "Distinct objects without UIDs should be valid.",
+			refs:   []PatchedObjectStatus{PatchPending(network, nil), PatchPending(subnet, ptr.To(types.UID("")))},
+		},
+		"DuplicateRef": {
+			reason: "A status reporting the same object twice should be invalid.",
+			refs: []PatchedObjectStatus{
+				PatchSuccess(network, ptr.To(types.UID("1")), "hash"),
+				PatchPending(subnet, nil),
+				PatchFailure(subnet, nil, errors.New("boom")),
+			},
+			want: errors.Errorf(errFmtDuplicateObjectRef, "Subnet/default/subnet"),
+		},
+		"DuplicateUID": {
+			reason: "A status reporting the same UID for different objects should be invalid.",
+			refs: []PatchedObjectStatus{
+				PatchSuccess(network, ptr.To(types.UID("1")), "hash"),
+				PatchPending(subnet, ptr.To(types.UID("1"))),
+			},
+			want: errors.Errorf(errFmtDuplicateUID, "1", "XNetwork/network", "Subnet/default/subnet"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &InControlPlaneOverrideStatus{ObjectRefs: tc.refs}
+			err := s.Validate()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffObjectRefs(t *testing.T) {
+	uid1, uid2 := types.UID("uid-1"), types.UID("uid-2")
+	cm := ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("default")}
+	xr := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+
+	cases := map[string]struct {
+		reason string
+		old    []PatchedObjectStatus
+		new    []PatchedObjectStatus
+		want   []ObjectRefTransition
+	}{
+		"NoChange": {
+			reason: "No transitions should be reported if no status has changed.",
+			old:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSuccess}},
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSuccess}},
+		},
+		"MatchedByUID": {
+			reason: "Objects should be matched by their UIDs when available.",
+			old: []PatchedObjectStatus{
+				{ObjectReference: cm, UID: &uid1, Status: PatchStateSuccess},
+				{ObjectReference: xr, UID: &uid2, Status: PatchStateSuccess},
+			},
+			new: []PatchedObjectStatus{
+				{ObjectReference: xr, UID: &uid2, Status: PatchStateSuccess},
+				{ObjectReference: cm, UID: &uid1, Status: PatchStateError},
+			},
+			want: []ObjectRefTransition{
+				{Object: PatchedObjectStatus{ObjectReference: cm, UID: &uid1, Status: PatchStateError}, Old: PatchStateSuccess, New: PatchStateError},
+			},
+		},
+		"MatchedByReference": {
+			reason: "Objects should be matched by their references when the previous status has no UID.",
+			old:    []PatchedObjectStatus{PatchPending(xr, nil)},
+			new:    []PatchedObjectStatus{{ObjectReference: xr, UID: &uid2, Status: PatchStateSuccess}},
+			want: []ObjectRefTransition{
+				{Object: PatchedObjectStatus{ObjectReference: xr, UID: &uid2, Status: PatchStateSuccess}, Old: PatchStatePending, New: PatchStateSuccess},
+			},
+		},
+		"RecreatedObject": {
+			reason: "An object recreated with a new UID should be reported as not previously reported rather than matched by its reference.",
+			old:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSuccess}},
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid2, Status: PatchStateSuccess}},
+			want: []ObjectRefTransition{
+				{Object: PatchedObjectStatus{ObjectReference: cm, UID: &uid2, Status: PatchStateSuccess}, New: PatchStateSuccess},
+			},
+		},
+		"NewObject": {
+			reason: "Objects not previously reported should be reported with an empty old state.",
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSkipped, Reason: PatchStateReasonConflict}},
+			want: []ObjectRefTransition{
+				{Object: PatchedObjectStatus{ObjectReference: cm, UID: &uid1, Status: PatchStateSkipped, Reason: PatchStateReasonConflict}, New: PatchStateSkipped},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DiffObjectRefs(tc.old, tc.new)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDiffObjectRefs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMetadataPatchValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		patch  MetadataPatch
+		policy AnnotationPolicy
+		want   error
+	}{
+		"Empty": {
+			reason: "A patch without annotations should be valid.",
+		},
+		"Allowed": {
+			reason: "A patch with only the allowed annotations should be valid.",
+			patch: MetadataPatch{
+				Annotations: map[string]string{
+					AnnotationKeyPaused:           "true",
+					AnnotationKeyForceReconcileAt: "2024-01-01T00:00:00Z",
+				},
+			},
+		},
+		"NotAllowed": {
+			reason: "A patch with an annotation that is not allowed should be invalid.",
+			patch: MetadataPatch{
+				Annotations: map[string]string{"example.org/foo": "bar"},
+			},
+			want: errors.Errorf(errFmtAnnotationNotAllowed, "example.org/foo"),
+		},
+		"AllowedByCustomPolicy": {
+			reason: "A patch with an annotation allowed by a custom policy should be valid.",
+			patch: MetadataPatch{
+				Annotations: map[string]string{"example.org/foo": "bar"},
+			},
+			policy: AnnotationPolicyFunc(func(key string) bool { return strings.HasPrefix(key, "example.org/") }),
+		},
+		"NotAllowedByCustomPolicy": {
+			reason: "A patch with an annotation allowed by default but not by a custom policy should be invalid.",
+			patch: MetadataPatch{
+				Annotations: map[string]string{AnnotationKeyPaused: "true"},
+			},
+			policy: AnnotationPolicyFunc(func(key string) bool { return strings.HasPrefix(key, "example.org/") }),
+			want:   errors.Errorf(errFmtAnnotationNotAllowed, AnnotationKeyPaused),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.patch.Validate(tc.policy)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMetadataPatchSemanticWarnings(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		patch  MetadataPatch
+		want   []string
+	}{
+		"Empty": {
+			reason: "A patch without annotations should not produce warnings.",
+		},
+		"Paused": {
+			reason: "Pausing alone should not produce warnings.",
+			patch:  MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: "true"}},
+		},
+		"ForceReconcile": {
+			reason: "Forcing a reconciliation alone should not produce warnings.",
+			patch:  MetadataPatch{Annotations: map[string]string{AnnotationKeyForceReconcileAt: "2024-01-01T00:00:00Z"}},
+		},
+		"UnpausedForceReconcile": {
+			reason: "Forcing a reconciliation while unpausing should not produce warnings.",
+			patch: MetadataPatch{Annotations: map[string]string{
+				AnnotationKeyPaused:           "",
+				AnnotationKeyForceReconcileAt: "2024-01-01T00:00:00Z",
+			}},
+		},
+		"PausedForceReconcile": {
+			reason: "Forcing a reconciliation while pausing should produce a warning.",
+			patch: MetadataPatch{Annotations: map[string]string{
+				AnnotationKeyPaused:           "true",
+				AnnotationKeyForceReconcileAt: "2024-01-01T00:00:00Z",
+			}},
+			want: []string{`annotation spaces.upbound.io/force-reconcile-at has no effect while annotation crossplane.io/paused is "true": the target objects will not be reconciled`},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.patch.SemanticWarnings()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSemanticWarnings(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOverridableFieldsInSyncWithValidation(t *testing.T) {
+	for _, f := range OverridableFields() {
+		if f.Path != FieldPathAnnotations {
+			t.Errorf("OverridableFields(): unexpected field path %q", f.Path)
+			continue
+		}
+		m := &MetadataPatch{Annotations: map[string]string{f.Key: "true"}}
+		if err := m.Validate(nil); err != nil {
+			t.Errorf("OverridableFields(): overridable annotation %q is rejected by validation: %v", f.Key, err)
+		}
+	}
+}
+
+func TestPruneObjectRefs(t *testing.T) {
+	cm := PatchedObjectStatus{
+		ObjectReference: ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("default")},
+		Status:          PatchStateSuccess,
+	}
+	xr := PatchedObjectStatus{
+		ObjectReference: ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"},
+		Status:          PatchStateSuccess,
+	}
+
+	cases := map[string]struct {
+		reason  string
+		refs    []PatchedObjectStatus
+		current []corev1.TypedObjectReference
+		want    []PatchedObjectStatus
+	}{
+		"AllPresent": {
+			reason: "No object statuses should be pruned if all objects are still in the hierarchy.",
+			refs:   []PatchedObjectStatus{cm, xr},
+			current: []corev1.TypedObjectReference{
+				{Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("default")},
+				{APIGroup: ptr.To("example.org"), Kind: "XNetwork", Name: "network"},
+			},
+			want: []PatchedObjectStatus{cm, xr},
+		},
+		"Stale": {
+			reason: "Object statuses should be pruned if their objects are no longer in the hierarchy.",
+			refs:   []PatchedObjectStatus{cm, xr},
+			current: []corev1.TypedObjectReference{
+				{APIGroup: ptr.To("example.org"), Kind: "XNetwork", Name: "network"},
+			},
+			want: []PatchedObjectStatus{xr},
+		},
+		"DifferentNamespace": {
+			reason: "Object statuses should be pruned if only an object in another namespace is in the hierarchy.",
+			refs:   []PatchedObjectStatus{cm},
+			current: []corev1.TypedObjectReference{
+				{Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("other")},
+			},
+			want: []PatchedObjectStatus{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PruneObjectRefs(tc.refs, tc.current)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPruneObjectRefs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewInControlPlaneOverride(t *testing.T) {
+	target := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+	override := Override{Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: "true"}}}
+
+	o := NewInControlPlaneOverride("ctp", target, override)
+	want := InControlPlaneOverrideSpec{
+		ControlPlaneName:  "ctp",
+		TargetRef:         target,
+		PropagationPolicy: PatchPropagateNone,
+		DeletionPolicy:    PatchDeletionRollBack,
+		Override:          override,
+	}
+	if diff := cmp.Diff(want, o.Spec); diff != "" {
+		t.Errorf("NewInControlPlaneOverride(...): -want spec, +got spec:\n%s", diff)
+	}
+	if diff := cmp.Diff("ctp-", o.GetGenerateName()); diff != "" {
+		t.Errorf("NewInControlPlaneOverride(...): -want generateName, +got generateName:\n%s", diff)
+	}
+	if err := o.Spec.Validate(); err != nil {
+		t.Errorf("NewInControlPlaneOverride(...): returned an invalid override: %v", err)
+	}
+}
+
+func TestNewPauseOverride(t *testing.T) {
+	target := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+	paused := Override{Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: "true"}}}
+
+	cases := map[string]struct {
+		reason  string
+		descend bool
+		want    InControlPlaneOverrideSpec
+	}{
+		"TargetOnly": {
+			reason: "Only the target should be paused if the override does not descend.",
+			want: InControlPlaneOverrideSpec{
+				ControlPlaneName:  "ctp",
+				TargetRef:         target,
+				PropagationPolicy: PatchPropagateNone,
+				DeletionPolicy:    PatchDeletionRollBack,
+				Override:          paused,
+			},
+		},
+		"Descend": {
+			reason:  "The target and its descendants should be paused if the override descends.",
+			descend: true,
+			want: InControlPlaneOverrideSpec{
+				ControlPlaneName:  "ctp",
+				TargetRef:         target,
+				PropagationPolicy: PatchPropagateDescending,
+				DeletionPolicy:    PatchDeletionRollBack,
+				Override:          paused,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := NewPauseOverride("ctp", target, tc.descend)
+			if diff := cmp.Diff(tc.want, o.Spec); diff != "" {
+				t.Errorf("\n%s\nNewPauseOverride(...): -want spec, +got spec:\n%s", tc.reason, diff)
+			}
+			if err := o.Spec.Validate(); err != nil {
+				t.Errorf("\n%s\nNewPauseOverride(...): returned an invalid override: %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestNewUnpauseOverride(t *testing.T) {
+	target := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+
+	for _, descend := range []bool{false, true} {
+		o := NewUnpauseOverride("ctp", target, descend)
+		want := NewPauseOverride("ctp", target, descend)
+		want.Spec.Override.Metadata.Annotations[AnnotationKeyPaused] = ""
+		if diff := cmp.Diff(want, o); diff != "" {
+			t.Errorf("NewUnpauseOverride(..., %t): -want, +got:\n%s", descend, diff)
+		}
+		if err := o.Spec.Validate(); err != nil {
+			t.Errorf("NewUnpauseOverride(..., %t): returned an invalid override: %v", descend, err)
+		}
+		paused := &metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyPaused: "true"}}
+		if !o.Spec.Override.Changes(paused) {
+			t.Errorf("NewUnpauseOverride(..., %t): should change a paused object", descend)
+		}
+	}
+}
+
+func TestFieldManager(t *testing.T) {
+	long := strings.Repeat("a", 253)
+
+	cases := map[string]struct {
+		reason    string
+		namespace string
+		name      string
+		want      string
+	}{
+		"Short": {
+			reason:    "The field manager should be derived from the override's namespace and name.",
+			namespace: "default",
+			name:      "pause-network",
+			want:      "spaces.upbound.io/override/default/pause-network",
+		},
+		"Long": {
+			reason:    "A field manager exceeding the maximum length should be truncated and suffixed with a hash.",
+			namespace: "default",
+			name:      long,
+			want:      "spaces.upbound.io/override/default/" + strings.Repeat("a", 76) + "-4a6f19b4d248f3a8",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &InControlPlaneOverride{ObjectMeta: metav1.ObjectMeta{Namespace: tc.namespace, Name: tc.name}}
+			got := o.FieldManager()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFieldManager(): -want, +got:\n%s", tc.reason, diff)
+			}
+			if len(got) > maxFieldManagerLength {
+				t.Errorf("\n%s\nFieldManager(): length %d exceeds %d", tc.reason, len(got), maxFieldManagerLength)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	target := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+	o := &InControlPlaneOverride{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "group", Name: "pause"},
+		Spec:       InControlPlaneOverrideSpec{ControlPlaneName: "ctp", TargetRef: target, PropagationPolicy: PatchPropagateNone},
+	}
+	if diff := cmp.Diff("group/ctp/example.org/v1alpha1/Network/default/network", o.Key()); diff != "" {
+		t.Errorf("Key(): -want, +got:\n%s", diff)
+	}
+
+	dup := o.DeepCopy()
+	dup.SetName("pause-all")
+	dup.Spec.PropagationPolicy = PatchPropagateDescending
+	if diff := cmp.Diff(o.Key(), dup.Key()); diff != "" {
+		t.Errorf("Key(): overrides of the same target should have the same key: -want, +got:\n%s", diff)
+	}
+
+	other := o.DeepCopy()
+	other.Spec.ControlPlaneName = "other"
+	if o.Key() == other.Key() {
+		t.Errorf("Key(): overrides of different ControlPlanes should have different keys, got %q", o.Key())
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	network := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+	cluster := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XCluster", Name: "cluster"}
+	override := func(ns, ctp string, target ObjectReference, p PatchPropagationPolicy) *InControlPlaneOverride {
+		return &InControlPlaneOverride{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns},
+			Spec: InControlPlaneOverrideSpec{
+				ControlPlaneName:  ctp,
+				TargetRef:         target,
+				PropagationPolicy: p,
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		a      *InControlPlaneOverride
+		b      *InControlPlaneOverride
+		want   bool
+	}{
+		"DifferentControlPlanes": {
+			reason: "Overrides targeting different control planes should not overlap.",
+			a:      override("default", "ctp1", network, PatchPropagateDescending),
+			b:      override("default", "ctp2", network, PatchPropagateDescending),
+		},
+		"DifferentNamespaces": {
+			reason: "Overrides in different namespaces should not overlap.",
+			a:      override("group1", "ctp", network, PatchPropagateNone),
+			b:      override("group2", "ctp", network, PatchPropagateNone),
+		},
+		"SameTarget": {
+			reason: "Overrides with the same target should overlap.",
+			a:      override("default", "ctp", network, PatchPropagateNone),
+			b:      override("default", "ctp", network, PatchPropagateNone),
+			want:   true,
+		},
+		"DifferentTargetsNoPropagation": {
+			reason: "Overrides with different targets that do not propagate should not overlap.",
+			a:      override("default", "ctp", network, PatchPropagateNone),
+			b:      override("default", "ctp", cluster, ""),
+		},
+		"DifferentTargetsWithPropagation": {
+			reason: "Overrides with different targets should conservatively overlap if either propagates.",
+			a:      override("default", "ctp", network, PatchPropagateNone),
+			b:      override("default", "ctp", cluster, PatchPropagateDescending),
+			want:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Overlaps(tc.a, tc.b)); diff != "" {
+				t.Errorf("\n%s\nOverlaps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInControlPlaneOverrideConditions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		set    []xpv1.Condition
+		want   xpv1.Condition
+	}{
+		"Unset": {
+			reason: "An unset condition should be reported as unknown.",
+			want:   xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown},
+		},
+		"Traversed": {
+			reason: "The Traversed condition should be retrievable after being set.",
+			set:    []xpv1.Condition{ReadyTraversed(corev1.ConditionTrue)},
+			want:   xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionTrue, Reason: ReasonTraversed},
+		},
+		"Deleted": {
+			reason: "The Deleted condition should replace a previously set Traversed condition.",
+			set:    []xpv1.Condition{ReadyTraversed(corev1.ConditionTrue), ReadyDeleted()},
+			want:   xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionFalse, Reason: ReasonDeleted},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &InControlPlaneOverride{}
+			for _, c := range tc.set {
+				o.SetConditions(c)
+			}
+			got := o.GetCondition(xpv1.TypeReady)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nGetCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInControlPlaneOverrideLifecycle(t *testing.T) {
+	type want struct {
+		traversed bool
+		deleting  bool
+	}
+	cases := map[string]struct {
+		reason string
+		cond   *xpv1.Condition
+		want   want
+	}{
+		"New": {
+			reason: "An override without a Ready condition should be neither traversed nor deleting.",
+		},
+		"InProgress": {
+			reason: "An override whose traversal is in progress should be neither traversed nor deleting.",
+			cond:   ptr.To(ReadyTraversed(corev1.ConditionFalse)),
+		},
+		"Traversed": {
+			reason: "An override whose traversal has completed should be traversed.",
+			cond:   ptr.To(ReadyTraversed(corev1.ConditionTrue)),
+			want:   want{traversed: true},
+		},
+		"Deleted": {
+			reason: "An override whose hierarchy has been cleaned up should be deleting.",
+			cond:   ptr.To(ReadyDeleted()),
+			want:   want{deleting: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &InControlPlaneOverride{}
+			if tc.cond != nil {
+				o.SetConditions(*tc.cond)
+			}
+			got := want{traversed: o.IsTraversed(), deleting: o.IsDeleting()}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nIsTraversed(), IsDeleting(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInControlPlaneOverrideCleanup(t *testing.T) {
+	o := &InControlPlaneOverride{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"example.org/other"}}}
+	o.AddFinalizer()
+	o.AddFinalizer()
+	if diff := cmp.Diff([]string{"example.org/other", OverrideFinalizer}, o.GetFinalizers()); diff != "" {
+		t.Errorf("\nAddFinalizer(): -want, +got:\n%s", diff)
+	}
+	o.MarkCleanedUp()
+	if !o.IsDeleting() {
+		t.Errorf("\nMarkCleanedUp(): IsDeleting() should be true after the hierarchy is cleaned up")
+	}
+	o.RemoveFinalizer()
+	o.RemoveFinalizer()
+	if diff := cmp.Diff([]string{"example.org/other"}, o.GetFinalizers()); diff != "" {
+		t.Errorf("\nRemoveFinalizer(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestMarkControlPlaneMissing(t *testing.T) {
+	o := NewInControlPlaneOverride("ctp", ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}, Override{})
+	o.SetConditions(ReadyTraversed(corev1.ConditionTrue))
+	if o.IsControlPlaneMissing() {
+		t.Errorf("\nIsControlPlaneMissing(): should be false before the ControlPlane is found missing")
+	}
+
+	o.MarkControlPlaneMissing()
+	want := xpv1.Condition{
+		Type:    xpv1.TypeReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  ReasonControlPlaneMissing,
+		Message: `control plane "ctp" does not exist`,
+	}
+	if diff := cmp.Diff(want, o.GetCondition(xpv1.TypeReady), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("\nMarkControlPlaneMissing(): -want, +got:\n%s", diff)
+	}
+	if !o.IsControlPlaneMissing() {
+		t.Errorf("\nIsControlPlaneMissing(): should be true after the ControlPlane is found missing")
+	}
+	if o.IsTraversed() || o.IsDeleting() {
+		t.Errorf("\nMarkControlPlaneMissing(): the override should be neither traversed nor deleting")
+	}
+}
+
+func TestValidateDryRun(t *testing.T) {
+	network := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+	subnet := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XSubnet", Name: "subnet"}
+
+	cases := map[string]struct {
+		reason string
+		dryRun *bool
+		refs   []PatchedObjectStatus
+		want   error
+	}{
+		"NotDryRun": {
+			reason: "Patched objects should be valid if the override is not a dry run.",
+			refs:   []PatchedObjectStatus{PatchSuccess(network, nil, "hash")},
+		},
+		"DryRunPlanned": {
+			reason: "Planned, pending, skipped and failed objects should be valid in a dry run.",
+			dryRun: ptr.To(true),
+			refs: []PatchedObjectStatus{
+				PatchPlanned(network, nil),
+				PatchPending(subnet, nil),
+				PatchNoChange(subnet, nil),
+				PatchFailure(subnet, nil, errors.New("boom")),
+			},
+		},
+		"DryRunPatched": {
+			reason: "A patched object should be reported as a mutation in a dry run.",
+			dryRun: ptr.To(true),
+			refs:   []PatchedObjectStatus{PatchPlanned(network, nil), PatchSuccess(subnet, nil, "hash")},
+			want:   errors.Errorf(errFmtDryRunPatched, "XSubnet/subnet"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := NewInControlPlaneOverride("ctp", network, Override{})
+			o.Spec.DryRun = tc.dryRun
+			o.Status.ObjectRefs = tc.refs
+			err := o.ValidateDryRun()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateDryRun(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSummaryPlanned(t *testing.T) {
+	ref := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+	s := &InControlPlaneOverrideStatus{ObjectRefs: []PatchedObjectStatus{PatchPlanned(ref, nil), PatchPlanned(ref, nil), PatchPending(ref, nil)}}
+	if diff := cmp.Diff(PatchSummary{Planned: 2, Pending: 1}, s.Summary()); diff != "" {
+		t.Errorf("Summary(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestEventMessage(t *testing.T) {
+	cm := ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("default")}
+	xr := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+
+	largePrefix := "0 succeeded, 0 planned, 0 pending, 0 skipped, 1000 failed; first error: XNetwork/network: "
+	large := InControlPlaneOverrideStatus{}
+	for i := 0; i < 1000; i++ {
+		large.ObjectRefs = append(large.ObjectRefs, PatchedObjectStatus{
+			ObjectReference: xr,
+			Status:          PatchStateError,
+			Message:         ptr.To(strings.Repeat("boom ", 200)),
+		})
+	}
+
+	// multiByte is a status whose message would be truncated within the
+	// three-byte rune "€" if it were truncated on a byte boundary.
+	multiBytePrefix := "0 succeeded, 0 planned, 0 pending, 0 skipped, 1 failed; first error: XNetwork/network: "
+	multiByteMessage := strings.Repeat("a", maxEventMessageLength-3-len(multiBytePrefix)-1) + strings.Repeat("€", 10)
+	multiByte := InControlPlaneOverrideStatus{ObjectRefs: []PatchedObjectStatus{
+		{ObjectReference: xr, Status: PatchStateError, Message: ptr.To(multiByteMessage)},
+	}}
+
+	cases := map[string]struct {
+		reason string
+		status InControlPlaneOverrideStatus
+		want   string
+	}{
+		"Empty": {
+			reason: "An empty status should report zero counts.",
+			want:   "0 succeeded, 0 planned, 0 pending, 0 skipped, 0 failed",
+		},
+		"Planned": {
+			reason: "Planned objects should be counted.",
+			status: InControlPlaneOverrideStatus{
+				ObjectRefs: []PatchedObjectStatus{
+					{ObjectReference: xr, Status: PatchStatePlanned},
+					{ObjectReference: cm, Status: PatchStatePlanned},
+				},
+			},
+			want: "0 succeeded, 2 planned, 0 pending, 0 skipped, 0 failed",
+		},
+		"MultiByte": {
+			reason: "A message should be truncated on a rune boundary.",
+			status: multiByte,
+			want:   multiBytePrefix + strings.Repeat("a", maxEventMessageLength-3-len(multiBytePrefix)-1) + "...",
+		},
+		"ErrorsAndSkips": {
+			reason: "The first error and the first skip should be reported.",
+			status: InControlPlaneOverrideStatus{
+				ObjectRefs: []PatchedObjectStatus{
+					{ObjectReference: xr, Status: PatchStateSuccess},
+					{ObjectReference: cm, Status: PatchStateSkipped, Reason: PatchStateReasonConflict},
+					{ObjectReference: xr, Status: PatchStateError, Message: ptr.To("boom\nbang")},
+					{ObjectReference: cm, Status: PatchStateError, Message: ptr.To("other")},
+				},
+			},
+			want: "1 succeeded, 0 planned, 0 pending, 1 skipped, 2 failed; first error: XNetwork/network: boom bang; first skipped: ConfigMap/default/cm: Conflict",
+		},
+		"Large": {
+			reason: "A status with long messages should be truncated.",
+			status: large,
+			want:   (largePrefix + strings.Repeat("boom ", 200))[:maxEventMessageLength-3] + "...",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.status.EventMessage()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEventMessage(): -want, +got:\n%s", tc.reason, diff)
+			}
+			if len(got) > maxEventMessageLength {
+				t.Errorf("\n%s\nEventMessage(): length %d exceeds %d", tc.reason, len(got), maxEventMessageLength)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("\n%s\nEventMessage(): %q is not valid UTF-8", tc.reason, got)
+			}
+		})
+	}
+}
+
+func TestOverrideHash(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		override *Override
+		want     string
+	}{
+		"Empty": {
+			reason:   "An empty override should hash its empty intent.",
+			override: &Override{},
+			want:     "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		},
+		"Paused": {
+			reason: "An override should hash its serialized intent.",
+			override: &Override{Metadata: &MetadataPatch{Annotations: map[string]string{
+				AnnotationKeyPaused: "true",
+			}}},
+			want: "2dac3d68e9ae1caabd695901d98cf3cb7eec8ef49c5851868cf3ae2d7ecc5006",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.override.Hash()
+			if err != nil {
+				t.Fatalf("\n%s\nHash(): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nHash(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchedObjectStatusIsUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status PatchedObjectStatus
+		hash   string
+		want   bool
+	}{
+		"Applied": {
+			reason: "An object successfully patched with the same intent should be up to date.",
+			status: PatchedObjectStatus{Status: PatchStateSuccess, PatchHash: "abc"},
+			hash:   "abc",
+			want:   true,
+		},
+		"Changed": {
+			reason: "An object patched with another intent should not be up to date.",
+			status: PatchedObjectStatus{Status: PatchStateSuccess, PatchHash: "abc"},
+			hash:   "def",
+		},
+		"Failed": {
+			reason: "An object whose patch has failed should not be up to date.",
+			status: PatchedObjectStatus{Status: PatchStateError, PatchHash: "abc"},
+			hash:   "abc",
+		},
+		"NoHash": {
+			reason: "An object without a recorded hash should not be up to date.",
+			status: PatchedObjectStatus{Status: PatchStateSuccess},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.status.IsUpToDate(tc.hash)); diff != "" {
+				t.Errorf("\n%s\nIsUpToDate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchFailure(t *testing.T) {
+	gr := schema.GroupResource{Group: "example.org", Resource: "xnetworks"}
+	ref := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+	uid := ptr.To(types.UID("uid"))
+
+	type want struct {
+		state     PatchState
+		reason    PatchStateReason
+		retryable bool
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Conflict": {
+			reason: "A conflict with another field manager should be skipped.",
+			err:    apierrors.NewConflict(gr, "network", errors.New("conflict with \"kubectl\"")),
+			want:   want{state: PatchStateSkipped, reason: PatchStateReasonConflict},
+		},
+		"SchemaMismatch": {
+			reason: "A field missing from the target's schema should be skipped as a schema mismatch.",
+			err:    apierrors.NewBadRequest("failed to create typed patch object: .spec.foo: field not declared in schema"),
+			want:   want{state: PatchStateSkipped, reason: PatchStateReasonSchemaMismatch},
+		},
+		"Invalid": {
+			reason: "An invalid patch should be skipped.",
+			err: apierrors.NewInvalid(schema.GroupKind{Group: "example.org", Kind: "XNetwork"}, "network",
+				field.ErrorList{field.Invalid(field.NewPath("metadata", "annotations"), "x", "invalid")}),
+			want: want{state: PatchStateSkipped, reason: PatchStateReasonInvalid},
+		},
+		"Forbidden": {
+			reason: "A forbidden patch should be skipped.",
+			err:    apierrors.NewForbidden(gr, "network", errors.New("denied")),
+			want:   want{state: PatchStateSkipped, reason: PatchStateReasonForbidden},
+		},
+		"NotFound": {
+			reason: "A patch of a deleted object should be skipped.",
+			err:    apierrors.NewNotFound(gr, "network"),
+			want:   want{state: PatchStateSkipped, reason: PatchStateReasonNotFound},
+		},
+		"Timeout": {
+			reason: "A timed out patch should be retried.",
+			err:    apierrors.NewTimeoutError("timed out", 1),
+			want:   want{state: PatchStateError, reason: PatchStateReasonTimeout, retryable: true},
+		},
+		"ServerTimeout": {
+			reason: "A patch that timed out on the server should be retried.",
+			err:    apierrors.NewServerTimeout(gr, "patch", 1),
+			want:   want{state: PatchStateError, reason: PatchStateReasonTimeout, retryable: true},
+		},
+		"Unknown": {
+			reason: "An unclassified error should be retried.",
+			err:    errors.New("boom"),
+			want:   want{state: PatchStateError, reason: PatchStateReasonUnknown, retryable: true},
+		},
+		"NilError": {
+			reason: "A nil error should be reported as an unclassified error without a message.",
+			want:   want{state: PatchStateError, reason: PatchStateReasonUnknown, retryable: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PatchFailure(ref, uid, tc.err)
+			wantStatus := PatchedObjectStatus{
+				ObjectReference: ref,
+				UID:             uid,
+				Status:          tc.want.state,
+				Reason:          tc.want.reason,
+			}
+			if tc.err != nil {
+				wantStatus.Message = ptr.To(tc.err.Error())
+			}
+			if diff := cmp.Diff(wantStatus, got); diff != "" {
+				t.Errorf("\n%s\nPatchFailure(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.retryable, got.Retryable()); diff != "" {
+				t.Errorf("\n%s\nRetryable(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOverrideChanges(t *testing.T) {
+	paused := &Override{Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: "true"}}}
+
+	cases := map[string]struct {
+		reason      string
+		override    *Override
+		annotations map[string]string
+		want        bool
+	}{
+		"NoMetadata": {
+			reason:   "An override without metadata should not change the object.",
+			override: &Override{},
+		},
+		"AlreadyPaused": {
+			reason:      "Pausing an already paused object should not change it.",
+			override:    paused,
+			annotations: map[string]string{AnnotationKeyPaused: "true", "example.org/owner": "team-a"},
+		},
+		"NotPaused": {
+			reason:   "Pausing an object without the paused annotation should change it.",
+			override: paused,
+			want:     true,
+		},
+		"Unpaused": {
+			reason:      "Pausing an explicitly unpaused object should change it.",
+			override:    paused,
+			annotations: map[string]string{AnnotationKeyPaused: "false"},
+			want:        true,
+		},
+		"ClearMissing": {
+			reason:   "Clearing an annotation the object does not have should change it, as the annotation is applied with an empty value.",
+			override: &Override{Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: ""}}},
+			want:     true,
+		},
+		"ClearExisting": {
+			reason:      "Clearing an annotation the object has with a value should change it.",
+			override:    &Override{Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: ""}}},
+			annotations: map[string]string{AnnotationKeyPaused: "true"},
+			want:        true,
+		},
+		"AlreadyCleared": {
+			reason:      "Clearing an annotation the object has with an empty value should not change it.",
+			override:    &Override{Metadata: &MetadataPatch{Annotations: map[string]string{AnnotationKeyPaused: ""}}},
+			annotations: map[string]string{AnnotationKeyPaused: ""},
+		},
+		"PartiallyMatching": {
+			reason: "An override should change the object if any of its annotations differs.",
+			override: &Override{Metadata: &MetadataPatch{Annotations: map[string]string{
+				AnnotationKeyPaused:           "true",
+				AnnotationKeyForceReconcileAt: "2024-05-01T00:00:00Z",
+			}}},
+			annotations: map[string]string{AnnotationKeyPaused: "true"},
+			want:        true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tc.annotations}
+			if diff := cmp.Diff(tc.want, tc.override.Changes(obj)); diff != "" {
+				t.Errorf("\n%s\nChanges(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// TestOverrideChangesRoundTrip checks that an object an Override's intent has
+// been applied to is not changed by the Override anymore.
+func TestOverrideChangesRoundTrip(t *testing.T) {
+	ref := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+
+	cases := map[string]struct {
+		reason      string
+		override    Override
+		annotations map[string]string
+	}{
+		"Pause": {
+			reason:   "An object a pause has been applied to should not be changed by the pause.",
+			override: NewPauseOverride("ctp", ref, false).Spec.Override,
+		},
+		"Unpause": {
+			reason:      "An object an unpause has been applied to should not be changed by the unpause.",
+			override:    NewUnpauseOverride("ctp", ref, false).Spec.Override,
+			annotations: map[string]string{AnnotationKeyPaused: "true"},
+		},
+		"ForceReconcile": {
+			reason: "An object a forced reconciliation has been applied to should not be changed by it.",
+			override: Override{Metadata: &MetadataPatch{Annotations: map[string]string{
+				AnnotationKeyPaused:           "",
+				AnnotationKeyForceReconcileAt: "2024-05-01T00:00:00Z",
+			}}},
+			annotations: map[string]string{"example.org/owner": "team-a"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			intent, err := tc.override.ToApplyConfiguration(ref)
+			if err != nil {
+				t.Fatalf("\n%s\nToApplyConfiguration(...): unexpected error: %v", tc.reason, err)
+			}
+			obj := &metav1.ObjectMeta{Annotations: map[string]string{}}
+			for k, v := range tc.annotations {
+				obj.Annotations[k] = v
+			}
+			for k, v := range intent.GetAnnotations() {
+				obj.Annotations[k] = v
+			}
+			if tc.override.Changes(obj) {
+				t.Errorf("\n%s\nChanges(...): want false after applying the intent, got true for annotations %v", tc.reason, obj.Annotations)
+			}
+		})
+	}
+}
+
+func TestValidatePatchStateTransition(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		from   PatchState
+		to     PatchState
+		want   error
+	}{
+		"NewToPending":     {reason: "A new object may start as Pending.", to: PatchStatePending},
+		"NewToSuccess":     {reason: "A new object may start as Success.", to: PatchStateSuccess},
+		"PendingToPending": {reason: "Pending may stay Pending.", from: PatchStatePending, to: PatchStatePending},
+		"PendingToSuccess": {reason: "Pending may move to Success.", from: PatchStatePending, to: PatchStateSuccess},
+		"PendingToSkipped": {reason: "Pending may move to Skipped.", from: PatchStatePending, to: PatchStateSkipped},
+		"PendingToError":   {reason: "Pending may move to Error.", from: PatchStatePending, to: PatchStateError},
+		"ErrorToPending":   {reason: "Error may be queued again.", from: PatchStateError, to: PatchStatePending},
+		"ErrorToSuccess":   {reason: "Error may move to Success on retry.", from: PatchStateError, to: PatchStateSuccess},
+		"ErrorToSkipped":   {reason: "Error may move to Skipped on retry.", from: PatchStateError, to: PatchStateSkipped},
+		"ErrorToError":     {reason: "Error may stay Error.", from: PatchStateError, to: PatchStateError},
+		"SuccessToSuccess": {reason: "Success may stay Success.", from: PatchStateSuccess, to: PatchStateSuccess},
+		"SuccessToSkipped": {reason: "Success may move to Skipped when patched again.", from: PatchStateSuccess, to: PatchStateSkipped},
+		"SuccessToError":   {reason: "Success may move to Error when patched again.", from: PatchStateSuccess, to: PatchStateError},
+		"SkippedToSuccess": {reason: "Skipped may move to Success when patched again.", from: PatchStateSkipped, to: PatchStateSuccess},
+		"SkippedToSkipped": {reason: "Skipped may stay Skipped.", from: PatchStateSkipped, to: PatchStateSkipped},
+		"SkippedToError":   {reason: "Skipped may move to Error when patched again.", from: PatchStateSkipped, to: PatchStateError},
+		"PendingToPlanned": {reason: "Pending may move to Planned in a dry run.", from: PatchStatePending, to: PatchStatePlanned},
+		"ErrorToPlanned":   {reason: "Error may move to Planned in a dry run.", from: PatchStateError, to: PatchStatePlanned},
+		"PlannedToPlanned": {reason: "Planned may stay Planned.", from: PatchStatePlanned, to: PatchStatePlanned},
+		"PlannedToPending": {reason: "Planned may be queued once the dry run is turned off.", from: PatchStatePlanned, to: PatchStatePending},
+		"PlannedToSuccess": {reason: "Planned may be patched once the dry run is turned off.", from: PatchStatePlanned, to: PatchStateSuccess},
+		"SuccessToPlanned": {
+			reason: "A patched object must not be planned again.",
+			from:   PatchStateSuccess,
+			to:     PatchStatePlanned,
+			want:   errors.Errorf(errFmtPatchStateTransition, PatchStateSuccess, PatchStatePlanned),
+		},
+		"SuccessToPending": {
+			reason: "Success must not move back to Pending.",
+			from:   PatchStateSuccess,
+			to:     PatchStatePending,
+			want:   errors.Errorf(errFmtPatchStateTransition, PatchStateSuccess, PatchStatePending),
+		},
+		"SkippedToPending": {
+			reason: "Skipped must not move back to Pending.",
+			from:   PatchStateSkipped,
+			to:     PatchStatePending,
+			want:   errors.Errorf(errFmtPatchStateTransition, PatchStateSkipped, PatchStatePending),
+		},
+		"UnknownFrom": {
+			reason: "An unknown old state should be rejected.",
+			from:   "Done",
+			to:     PatchStateSuccess,
+			want:   errors.Errorf(errFmtInvalidPatchState, "Done"),
+		},
+		"UnknownTo": {
+			reason: "An unknown new state should be rejected.",
+			from:   PatchStatePending,
+			to:     "Done",
+			want:   errors.Errorf(errFmtInvalidPatchState, "Done"),
+		},
+		"EmptyTo": {
+			reason: "An empty new state should be rejected.",
+			from:   PatchStatePending,
+			want:   errors.Errorf(errFmtInvalidPatchState, ""),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePatchStateTransition(tc.from, tc.to)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidatePatchStateTransition(%q, %q): -want error, +got error:\n%s", tc.reason, tc.from, tc.to, diff)
+			}
+		})
+	}
+}
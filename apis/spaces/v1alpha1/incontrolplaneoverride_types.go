@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // +kubebuilder:object:root=true
@@ -83,6 +85,15 @@ const (
 	PatchDeletionKeep PatchDeletionPolicy = "Keep"
 )
 
+const (
+	// AnnotationKeyPaused is the Crossplane annotation that pauses the
+	// reconciliation of an object when set to "true".
+	AnnotationKeyPaused = "crossplane.io/paused"
+	// AnnotationKeyForceReconcileAt is the annotation that forces the
+	// reconciliation of an object at the specified time.
+	AnnotationKeyForceReconcileAt = "spaces.upbound.io/force-reconcile-at"
+)
+
 // MetadataPatch represents the Kube object metadata.
 type MetadataPatch struct {
 	// Annotations represents the Kube object annotations.
@@ -170,6 +181,13 @@ type InControlPlaneOverrideSpec struct {
 	// object hierarchy. The fully specified intent is obtained by serializing
 	// the Override.
 	Override Override `json:"override"`
+
+	// DryRun specifies whether the configuration override is only planned
+	// rather than applied. A dry run reports the objects in the target
+	// object hierarchy that would be patched with the Planned status,
+	// without patching them.
+	// +optional
+	DryRun *bool `json:"dryRun,omitempty"`
 }
 
 // PatchState denotes the result of the patch operation on the associated
@@ -177,12 +195,21 @@ type InControlPlaneOverrideSpec struct {
 type PatchState string
 
 const (
+	// PatchStateSuccess denotes that the target object was successfully
+	// patched.
+	PatchStateSuccess PatchState = "Success"
+	// PatchStatePending denotes that the target object has been queued for
+	// patching but has not been processed yet.
+	PatchStatePending PatchState = "Pending"
 	// PatchStateSkipped denotes that the target object was skipped.
 	// The reason for the skip is specified in the `reason` field.
 	PatchStateSkipped PatchState = "Skipped"
 	// PatchStateError denotes that there was a transient error while patching
 	// the object.
 	PatchStateError PatchState = "Error"
+	// PatchStatePlanned denotes that the target object would be patched but
+	// has not been, as the InControlPlaneOverride is a dry run.
+	PatchStatePlanned PatchState = "Planned"
 )
 
 // PatchStateReason denotes why a patch operation on the associated
@@ -198,6 +225,29 @@ const (
 	// the associated target object has been skipped due to a schema mismatch
 	// between the fully specified intent and the object's schema.
 	PatchStateReasonSchemaMismatch PatchStateReason = "SchemaMismatch"
+	// PatchStateReasonInvalid denotes that the patch operation on the
+	// associated target object has been skipped because the API server
+	// rejected the patched object as invalid.
+	PatchStateReasonInvalid PatchStateReason = "Invalid"
+	// PatchStateReasonForbidden denotes that the patch operation on the
+	// associated target object has been skipped because the controller is
+	// not allowed to patch it.
+	PatchStateReasonForbidden PatchStateReason = "Forbidden"
+	// PatchStateReasonNotFound denotes that the patch operation on the
+	// associated target object has been skipped because the object no
+	// longer exists.
+	PatchStateReasonNotFound PatchStateReason = "NotFound"
+	// PatchStateReasonTimeout denotes that the patch operation on the
+	// associated target object has timed out and will be retried.
+	PatchStateReasonTimeout PatchStateReason = "Timeout"
+	// PatchStateReasonUnknown denotes that the patch operation on the
+	// associated target object has failed for an unclassified reason and
+	// will be retried.
+	PatchStateReasonUnknown PatchStateReason = "Unknown"
+	// PatchStateReasonNoChange denotes that the patch operation on the
+	// associated target object has been skipped because the object already
+	// has the desired configuration, e.g., it is already paused.
+	PatchStateReasonNoChange PatchStateReason = "NoChange"
 )
 
 // PatchedObjectStatus represents the state of an applied patch to an object
@@ -212,7 +262,7 @@ type PatchedObjectStatus struct {
 	UID *types.UID `json:"uid,omitempty"`
 
 	// Status of the configuration override.
-	// +kubebuilder:validation:Enum=Success;Skipped;Error
+	// +kubebuilder:validation:Enum=Success;Pending;Skipped;Error;Planned
 	Status PatchState `json:"status"`
 
 	// Reason is the reason for the target objects override Status.
@@ -221,6 +271,18 @@ type PatchedObjectStatus struct {
 	// Message holds an optional detail message detailing the observed state.
 	// +optional
 	Message *string `json:"message,omitempty"`
+
+	// PatchHash is the hash of the fully specified intent last applied to
+	// the object. It is used to skip re-applying an unchanged intent.
+	// +optional
+	PatchHash string `json:"patchHash,omitempty"`
+
+	// Attempts is the number of consecutive failed attempts to patch the
+	// object. It is used to back off retries of the object and is reset
+	// once the object is successfully patched.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Attempts int32 `json:"attempts,omitempty"`
 }
 
 // String returns a string representation of the PatchedObjectStatus.
@@ -244,6 +306,25 @@ type InControlPlaneOverrideStatus struct {
 	ObjectRefs []PatchedObjectStatus `json:"objectRefs,omitempty"`
 }
 
+const (
+	// OverrideFinalizer is the finalizer that InControlPlaneOverrides carry
+	// until their target object hierarchy has been cleaned up.
+	OverrideFinalizer = "spaces.upbound.io/override-cleanup"
+)
+
+const (
+	// ReasonTraversed indicates that the target object hierarchy of an
+	// InControlPlaneOverride has been traversed.
+	ReasonTraversed xpv1.ConditionReason = "Traversed"
+	// ReasonDeleted indicates that the target object hierarchy of an
+	// InControlPlaneOverride has been cleaned up.
+	ReasonDeleted xpv1.ConditionReason = "Deleted"
+	// ReasonControlPlaneMissing indicates that the ControlPlane targeted by
+	// an InControlPlaneOverride does not exist, so its target object
+	// hierarchy cannot be patched.
+	ReasonControlPlaneMissing xpv1.ConditionReason = "ControlPlaneMissing"
+)
+
 // ReadyDeleted returns a condition that indicates the target object hierarchy
 // has successfully been cleaned up, and the InControlPlaneOverride object is
 // ready for garbage collection.
@@ -252,7 +333,19 @@ func ReadyDeleted() xpv1.Condition {
 		Type:               xpv1.TypeReady,
 		Status:             corev1.ConditionFalse,
 		LastTransitionTime: metav1.Now(),
-		Reason:             "Deleted",
+		Reason:             ReasonDeleted,
+	}
+}
+
+// ReadyControlPlaneMissing returns a condition that indicates the targeted
+// ControlPlane does not exist.
+func ReadyControlPlaneMissing(ctpName string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonControlPlaneMissing,
+		Message:            fmt.Sprintf("control plane %q does not exist", ctpName),
 	}
 }
 
@@ -263,10 +356,76 @@ func ReadyTraversed(s corev1.ConditionStatus) xpv1.Condition {
 		Type:               xpv1.TypeReady,
 		Status:             s,
 		LastTransitionTime: metav1.Now(),
-		Reason:             "Traversed",
+		Reason:             ReasonTraversed,
 	}
 }
 
+// GetCondition of this InControlPlaneOverride.
+func (o *InControlPlaneOverride) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return o.Status.GetCondition(ct)
+}
+
+// SetConditions of this InControlPlaneOverride.
+func (o *InControlPlaneOverride) SetConditions(c ...xpv1.Condition) {
+	o.Status.SetConditions(c...)
+}
+
+// GetControlPlaneName returns the name of the ControlPlane this
+// InControlPlaneOverride targets.
+func (o *InControlPlaneOverride) GetControlPlaneName() string {
+	return o.Spec.ControlPlaneName
+}
+
+// IsTraversed returns true if the target object hierarchy of this
+// InControlPlaneOverride has successfully been traversed.
+func (o *InControlPlaneOverride) IsTraversed() bool {
+	c := o.GetCondition(xpv1.TypeReady)
+	return c.Status == corev1.ConditionTrue && c.Reason == ReasonTraversed
+}
+
+// IsDeleting returns true if the target object hierarchy of this
+// InControlPlaneOverride has been cleaned up and the InControlPlaneOverride
+// is ready for garbage collection.
+func (o *InControlPlaneOverride) IsDeleting() bool {
+	c := o.GetCondition(xpv1.TypeReady)
+	return c.Status == corev1.ConditionFalse && c.Reason == ReasonDeleted
+}
+
+// IsControlPlaneMissing returns true if the ControlPlane targeted by this
+// InControlPlaneOverride has been found not to exist.
+func (o *InControlPlaneOverride) IsControlPlaneMissing() bool {
+	c := o.GetCondition(xpv1.TypeReady)
+	return c.Status == corev1.ConditionFalse && c.Reason == ReasonControlPlaneMissing
+}
+
+// MarkControlPlaneMissing marks this InControlPlaneOverride with the
+// ReadyControlPlaneMissing condition, as its target object hierarchy cannot be
+// patched while the targeted ControlPlane does not exist. Controllers should
+// stop retrying the InControlPlaneOverride until the ControlPlane is created.
+func (o *InControlPlaneOverride) MarkControlPlaneMissing() {
+	o.SetConditions(ReadyControlPlaneMissing(o.GetControlPlaneName()))
+}
+
+// MarkCleanedUp marks the target object hierarchy of this
+// InControlPlaneOverride as cleaned up with the ReadyDeleted condition. The
+// OverrideFinalizer should then be removed with RemoveFinalizer so that the
+// InControlPlaneOverride can be garbage collected.
+func (o *InControlPlaneOverride) MarkCleanedUp() {
+	o.SetConditions(ReadyDeleted())
+}
+
+// AddFinalizer adds the OverrideFinalizer to this InControlPlaneOverride if
+// it does not already have it.
+func (o *InControlPlaneOverride) AddFinalizer() {
+	controllerutil.AddFinalizer(o, OverrideFinalizer)
+}
+
+// RemoveFinalizer removes the OverrideFinalizer from this
+// InControlPlaneOverride.
+func (o *InControlPlaneOverride) RemoveFinalizer() {
+	controllerutil.RemoveFinalizer(o, OverrideFinalizer)
+}
+
 var (
 	// InControlPlaneOverrideKind is the kind of the InControlPlaneOverride.
 	InControlPlaneOverrideKind = reflect.TypeOf(InControlPlaneOverride{}).Name()
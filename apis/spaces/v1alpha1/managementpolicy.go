@@ -0,0 +1,66 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"sort"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// allManagementActions are the actions the "*" management action expands to.
+var allManagementActions = []xpv1.ManagementAction{
+	xpv1.ManagementActionObserve,
+	xpv1.ManagementActionCreate,
+	xpv1.ManagementActionUpdate,
+	xpv1.ManagementActionDelete,
+	xpv1.ManagementActionLateInitialize,
+}
+
+// ManagementPolicyDelta returns the management actions that the desired
+// management policies add to and remove from the current ones, each sorted
+// lexically. The "*" action is expanded to all the actions it stands for, so
+// that, e.g., replacing {"*"} with an explicit set of actions only reports
+// the actions missing from the set as removed.
+func ManagementPolicyDelta(current, desired xpv1.ManagementPolicies) (added, removed []string) {
+	cur, des := expandManagementPolicies(current), expandManagementPolicies(desired)
+	for a := range des {
+		if _, ok := cur[a]; !ok {
+			added = append(added, string(a))
+		}
+	}
+	for a := range cur {
+		if _, ok := des[a]; !ok {
+			removed = append(removed, string(a))
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func expandManagementPolicies(p xpv1.ManagementPolicies) map[xpv1.ManagementAction]struct{} {
+	actions := make(map[xpv1.ManagementAction]struct{}, len(p))
+	for _, a := range p {
+		if a != xpv1.ManagementActionAll {
+			actions[a] = struct{}{}
+			continue
+		}
+		for _, e := range allManagementActions {
+			actions[e] = struct{}{}
+		}
+	}
+	return actions
+}
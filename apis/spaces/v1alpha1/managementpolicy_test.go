@@ -0,0 +1,78 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManagementPolicyDelta(t *testing.T) {
+	type want struct {
+		added   []string
+		removed []string
+	}
+	cases := map[string]struct {
+		reason  string
+		current xpv1.ManagementPolicies
+		desired xpv1.ManagementPolicies
+		want    want
+	}{
+		"Unchanged": {
+			reason:  "Identical policies should not add or remove any actions.",
+			current: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			desired: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+		},
+		"WildcardToObserve": {
+			reason:  "Replacing the wildcard with Observe should remove all the other actions.",
+			current: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+			desired: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			want:    want{removed: []string{"Create", "Delete", "LateInitialize", "Update"}},
+		},
+		"ObserveToWildcard": {
+			reason:  "Replacing Observe with the wildcard should add all the other actions.",
+			current: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			desired: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+			want:    want{added: []string{"Create", "Delete", "LateInitialize", "Update"}},
+		},
+		"WildcardToExplicitAll": {
+			reason:  "Replacing the wildcard with all the actions listed explicitly should not add or remove any actions.",
+			current: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+			desired: xpv1.ManagementPolicies{
+				xpv1.ManagementActionObserve,
+				xpv1.ManagementActionCreate,
+				xpv1.ManagementActionUpdate,
+				xpv1.ManagementActionDelete,
+				xpv1.ManagementActionLateInitialize,
+			},
+		},
+		"Explicit": {
+			reason:  "Actions only in the desired policies should be added and actions only in the current ones removed.",
+			current: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionDelete},
+			desired: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionUpdate, xpv1.ManagementActionCreate},
+			want:    want{added: []string{"Create", "Update"}, removed: []string{"Delete"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			added, removed := ManagementPolicyDelta(tc.current, tc.desired)
+			if diff := cmp.Diff(tc.want, want{added: added, removed: removed}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nManagementPolicyDelta(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
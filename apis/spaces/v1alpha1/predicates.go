@@ -0,0 +1,36 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// controlPlaneTargeter is implemented by the override types that target a
+// ControlPlane.
+type controlPlaneTargeter interface {
+	GetControlPlaneName() string
+}
+
+// ForControlPlane returns a predicate that only passes the events of the
+// overrides targeting the ControlPlane with the supplied name. Events of the
+// objects that do not target a ControlPlane are filtered out.
+func ForControlPlane(name string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(o client.Object) bool {
+		t, ok := o.(controlPlaneTargeter)
+		return ok && t.GetControlPlaneName() == name
+	})
+}
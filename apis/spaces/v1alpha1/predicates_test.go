@@ -0,0 +1,61 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestForControlPlane(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		obj    client.Object
+		want   bool
+	}{
+		"Match": {
+			reason: "An override targeting the ControlPlane should pass.",
+			obj:    &InControlPlaneOverride{Spec: InControlPlaneOverrideSpec{ControlPlaneName: "ctp"}},
+			want:   true,
+		},
+		"OtherControlPlane": {
+			reason: "An override targeting another ControlPlane should be filtered out.",
+			obj:    &InControlPlaneOverride{Spec: InControlPlaneOverrideSpec{ControlPlaneName: "other"}},
+		},
+		"NotAnOverride": {
+			reason: "An object that does not target a ControlPlane should be filtered out.",
+			obj:    &corev1.ConfigMap{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := ForControlPlane("ctp")
+			got := map[string]bool{
+				"create":  p.Create(event.CreateEvent{Object: tc.obj}),
+				"update":  p.Update(event.UpdateEvent{ObjectOld: tc.obj, ObjectNew: tc.obj}),
+				"delete":  p.Delete(event.DeleteEvent{Object: tc.obj}),
+				"generic": p.Generic(event.GenericEvent{Object: tc.obj}),
+			}
+			want := map[string]bool{"create": tc.want, "update": tc.want, "delete": tc.want, "generic": tc.want}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nForControlPlane(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"hash/fnv"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	// retryBaseDelay is the delay before the first retry of a failed patch.
+	retryBaseDelay = time.Second
+	// retryMaxDelay caps the delay between the retries of a failed patch.
+	retryMaxDelay = 5 * time.Minute
+	// retryJitterPermille is the maximum jitter subtracted from a retry
+	// delay, in thousandths of the delay.
+	retryJitterPermille = 100
+)
+
+// NextRetry returns the delay before retrying to patch the referenced object
+// after the supplied number of failed attempts, as recorded in the Attempts of
+// its PatchedObjectStatus. The delay doubles with every attempt starting from
+// one second and is capped at five minutes. Up to 10% of the delay is
+// subtracted as jitter so that objects failing together are not retried
+// together. The jitter is derived from the reference and the attempt, so the
+// delay is stable across reconciles.
+func NextRetry(ref ObjectReference, attempt int) time.Duration {
+	d := retryMaxDelay
+	// beyond this many doublings the base delay exceeds the cap.
+	if attempt < 32 {
+		d = min(retryBaseDelay<<max(attempt-1, 0), retryMaxDelay)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(objectKey(ref) + "/" + strconv.Itoa(attempt)))
+	jitter := d / 1000 * time.Duration(h.Sum32()%(retryJitterPermille+1))
+	return d - jitter
+}
+
+// CarryAttempts returns the statuses in newRefs with their Attempts set from
+// the statuses previously reported for the same objects in oldRefs. An object
+// that has failed with a transient error, i.e., whose status is Error, has
+// one more failed attempt than previously reported, while the Attempts of any
+// other object are reset. Objects are matched as by DiffObjectRefs.
+func CarryAttempts(oldRefs, newRefs []PatchedObjectStatus) []PatchedObjectStatus {
+	idx := newObjectIndex(oldRefs)
+	carried := make([]PatchedObjectStatus, len(newRefs))
+	for i, r := range newRefs {
+		r.Attempts = 0
+		if r.Status == PatchStateError {
+			old, _ := idx.lookup(r)
+			r.Attempts = old.Attempts
+			if r.Attempts < math.MaxInt32 {
+				r.Attempts++
+			}
+		}
+		carried[i] = r
+	}
+	return carried
+}
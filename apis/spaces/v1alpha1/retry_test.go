@@ -0,0 +1,157 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestNextRetry(t *testing.T) {
+	ref := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+
+	cases := map[string]struct {
+		reason  string
+		attempt int
+		want    time.Duration
+	}{
+		"NoAttempts": {
+			reason: "An object without failed attempts should be retried after the base delay.",
+			want:   time.Second,
+		},
+		"FirstAttempt": {
+			reason:  "The first retry should happen after the base delay.",
+			attempt: 1,
+			want:    time.Second,
+		},
+		"Exponential": {
+			reason:  "The delay should double with every attempt.",
+			attempt: 4,
+			want:    8 * time.Second,
+		},
+		"LastBeforeCap": {
+			reason:  "The delay should not be capped while it is below the maximum delay.",
+			attempt: 9,
+			want:    256 * time.Second,
+		},
+		"Capped": {
+			reason:  "The delay should be capped at the maximum delay.",
+			attempt: 10,
+			want:    5 * time.Minute,
+		},
+		"CappedWithoutOverflow": {
+			reason:  "A large number of attempts should not overflow the delay.",
+			attempt: 64,
+			want:    5 * time.Minute,
+		},
+		"CappedMaxInt": {
+			reason:  "The maximum number of attempts should not overflow the delay.",
+			attempt: math.MaxInt,
+			want:    5 * time.Minute,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NextRetry(ref, tc.attempt)
+			if got > tc.want || got < tc.want*9/10 {
+				t.Errorf("\n%s\nNextRetry(..., %d): want a delay in [%s, %s], got %s", tc.reason, tc.attempt, tc.want*9/10, tc.want, got)
+			}
+			if again := NextRetry(ref, tc.attempt); again != got {
+				t.Errorf("\n%s\nNextRetry(..., %d): want a stable delay %s, got %s", tc.reason, tc.attempt, got, again)
+			}
+		})
+	}
+}
+
+func TestNextRetryJitter(t *testing.T) {
+	delays := map[time.Duration]bool{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		delays[NextRetry(ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: name}, 20)] = true
+	}
+	if len(delays) < 2 {
+		t.Errorf("NextRetry(...): want jittered delays for different objects, got %v", delays)
+	}
+}
+
+func TestCarryAttempts(t *testing.T) {
+	uid1, uid2 := types.UID("uid-1"), types.UID("uid-2")
+	cm := ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("default")}
+	xr := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"}
+
+	cases := map[string]struct {
+		reason string
+		old    []PatchedObjectStatus
+		new    []PatchedObjectStatus
+		want   []PatchedObjectStatus
+	}{
+		"FirstFailure": {
+			reason: "An object failing for the first time should have one failed attempt.",
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError}},
+			want:   []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: 1}},
+		},
+		"ConsecutiveFailure": {
+			reason: "An object failing again should have one more failed attempt than previously reported.",
+			old: []PatchedObjectStatus{
+				{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: 3},
+				{ObjectReference: xr, Status: PatchStateError, Attempts: 1},
+			},
+			new: []PatchedObjectStatus{
+				{ObjectReference: xr, UID: &uid2, Status: PatchStateError},
+				{ObjectReference: cm, UID: &uid1, Status: PatchStateError},
+			},
+			want: []PatchedObjectStatus{
+				{ObjectReference: xr, UID: &uid2, Status: PatchStateError, Attempts: 2},
+				{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: 4},
+			},
+		},
+		"Success": {
+			reason: "The failed attempts of an object should be reset once it is successfully patched.",
+			old:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: 3}},
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSuccess, Attempts: 3}},
+			want:   []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSuccess}},
+		},
+		"Skipped": {
+			reason: "The failed attempts of an object should be reset once it is skipped, as it will not be retried.",
+			old:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: 3}},
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSkipped, Reason: PatchStateReasonForbidden}},
+			want:   []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateSkipped, Reason: PatchStateReasonForbidden}},
+		},
+		"RecreatedObject": {
+			reason: "A recreated object should not inherit the failed attempts of the object it replaces.",
+			old:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: 3}},
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid2, Status: PatchStateError}},
+			want:   []PatchedObjectStatus{{ObjectReference: cm, UID: &uid2, Status: PatchStateError, Attempts: 1}},
+		},
+		"MaxAttempts": {
+			reason: "The failed attempts should not overflow.",
+			old:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: math.MaxInt32}},
+			new:    []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError}},
+			want:   []PatchedObjectStatus{{ObjectReference: cm, UID: &uid1, Status: PatchStateError, Attempts: math.MaxInt32}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CarryAttempts(tc.old, tc.new)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCarryAttempts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errFmtGetTarget      = "cannot get the target object %s"
+	errFmtResolveVersion = "cannot resolve the API version of %s"
+)
+
+// TargetExists returns true if the referenced object exists. It returns false
+// without an error if the object is not found, so that a missing target can
+// be reported before attempting to patch it.
+func TargetExists(ctx context.Context, reader client.Reader, ref ObjectReference) (bool, error) {
+	u := ref.ToUnstructured()
+	err := reader.Get(ctx, client.ObjectKeyFromObject(u), u)
+	switch {
+	case err == nil:
+		return true, nil
+	case apierrors.IsNotFound(err):
+		return false, nil
+	default:
+		return false, errors.Wrapf(err, errFmtGetTarget, objectName(ref))
+	}
+}
+
+// ToUnstructured returns an unstructured object identifying the referenced
+// object, e.g., to be fetched with a client. The legacy "core" group of an
+// apiVersion such as "core/v1" is normalized to the empty core group.
+func (r *ObjectReference) ToUnstructured() *unstructured.Unstructured {
+	gvk := schema.FromAPIVersionAndKind(r.APIVersion, r.Kind)
+	if gvk.Group == "core" {
+		gvk.Group = ""
+	}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(r.Name)
+	u.SetNamespace(ptr.Deref(r.Namespace, ""))
+	return u
+}
+
+// ToUnstructured returns an unstructured object identifying the patched
+// object, including its UID if known, e.g., to re-fetch the object and verify
+// that it is the object that has been patched.
+func (r *PatchedObjectStatus) ToUnstructured() *unstructured.Unstructured {
+	u := r.ObjectReference.ToUnstructured()
+	u.SetUID(ptr.Deref(r.UID, ""))
+	return u
+}
+
+// ResolveAPIVersion returns the apiVersion of the referenced object using the
+// version the supplied REST mapper prefers for its group and kind, as typed
+// object references only carry the API group. An error is returned if the
+// kind is not known to the REST mapper.
+func ResolveAPIVersion(ref corev1.TypedObjectReference, restMapper meta.RESTMapper) (string, error) {
+	gk := schema.GroupKind{Group: ptr.Deref(ref.APIGroup, ""), Kind: ref.Kind}
+	m, err := restMapper.RESTMapping(gk)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtResolveVersion, gk)
+	}
+	return m.GroupVersionKind.GroupVersion().String(), nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTargetExists(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+
+	type want struct {
+		exists bool
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		reader client.Reader
+		want   want
+	}{
+		"Exists": {
+			reason: "An existing target should be reported as existing.",
+			reader: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				if key != (client.ObjectKey{Namespace: "default", Name: "network"}) {
+					return errors.Errorf("unexpected key %s", key)
+				}
+				if gvk := obj.GetObjectKind().GroupVersionKind(); gvk != (schema.GroupVersionKind{Group: "example.org", Version: "v1alpha1", Kind: "Network"}) {
+					return errors.Errorf("unexpected GVK %s", gvk)
+				}
+				return nil
+			}},
+			want: want{exists: true},
+		},
+		"NotFound": {
+			reason: "A missing target should be reported as not existing without an error.",
+			reader: &test.MockClient{MockGet: test.NewMockGetFn(apierrors.NewNotFound(schema.GroupResource{Group: "example.org", Resource: "networks"}, "network"))},
+		},
+		"GetError": {
+			reason: "Any other error should be returned.",
+			reader: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetTarget, "Network/default/network")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			exists, err := TargetExists(context.Background(), tc.reader, ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nTargetExists(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.exists, exists); diff != "" {
+				t.Errorf("\n%s\nTargetExists(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveAPIVersion(t *testing.T) {
+	v1alpha1 := schema.GroupVersion{Group: "example.org", Version: "v1alpha1"}
+	v1beta1 := schema.GroupVersion{Group: "example.org", Version: "v1beta1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion, v1beta1, v1alpha1})
+	mapper.Add(v1alpha1.WithKind("XNetwork"), meta.RESTScopeRoot)
+	mapper.Add(v1beta1.WithKind("XNetwork"), meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+	type want struct {
+		apiVersion string
+		err        error
+	}
+	cases := map[string]struct {
+		reason string
+		ref    corev1.TypedObjectReference
+		want   want
+	}{
+		"PreferredVersion": {
+			reason: "The preferred version of the group should be resolved.",
+			ref:    corev1.TypedObjectReference{APIGroup: ptr.To("example.org"), Kind: "XNetwork", Name: "network"},
+			want:   want{apiVersion: "example.org/v1beta1"},
+		},
+		"CoreGroup": {
+			reason: "A reference without an API group should resolve to the core group.",
+			ref:    corev1.TypedObjectReference{Kind: "ConfigMap", Name: "cm"},
+			want:   want{apiVersion: "v1"},
+		},
+		"UnknownKind": {
+			reason: "A kind unknown to the REST mapper should return an error.",
+			ref:    corev1.TypedObjectReference{APIGroup: ptr.To("example.org"), Kind: "XCluster", Name: "cluster"},
+			want: want{err: errors.Wrapf(&meta.NoResourceMatchError{PartialResource: schema.GroupVersionResource{Group: "example.org", Resource: "XCluster"}},
+				errFmtResolveVersion, schema.GroupKind{Group: "example.org", Kind: "XCluster"})},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveAPIVersion(tc.ref, mapper)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveAPIVersion(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.apiVersion, got); diff != "" {
+				t.Errorf("\n%s\nResolveAPIVersion(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObjectReferenceToUnstructured(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ref    ObjectReference
+		want   map[string]any
+	}{
+		"Namespaced": {
+			reason: "A namespaced reference should yield an object with a namespace.",
+			ref:    ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")},
+			want: map[string]any{
+				"apiVersion": "example.org/v1alpha1",
+				"kind":       "Network",
+				"metadata":   map[string]any{"name": "network", "namespace": "default"},
+			},
+		},
+		"ClusterScoped": {
+			reason: "A cluster-scoped reference should yield an object without a namespace.",
+			ref:    ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "XNetwork", Name: "network"},
+			want: map[string]any{
+				"apiVersion": "example.org/v1alpha1",
+				"kind":       "XNetwork",
+				"metadata":   map[string]any{"name": "network"},
+			},
+		},
+		"CoreGroup": {
+			reason: "A core group reference should yield an object in the core group.",
+			ref:    ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("default")},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "cm", "namespace": "default"},
+			},
+		},
+		"LegacyCoreGroup": {
+			reason: "The legacy core group name should be normalized to the empty core group.",
+			ref:    ObjectReference{APIVersion: "core/v1", Kind: "ConfigMap", Name: "cm", Namespace: ptr.To("default")},
+			want: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "cm", "namespace": "default"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.ref.ToUnstructured()
+			if diff := cmp.Diff(tc.want, got.Object); diff != "" {
+				t.Errorf("\n%s\nToUnstructured(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchedObjectStatusToUnstructured(t *testing.T) {
+	ref := ObjectReference{APIVersion: "example.org/v1alpha1", Kind: "Network", Name: "network", Namespace: ptr.To("default")}
+	uid := types.UID("b9a1c6d2-4f0e-4c1a-9d6e-2f7c3a8e5b10")
+
+	s := PatchSuccess(ref, ptr.To(uid), "hash")
+	u := s.ToUnstructured()
+	if diff := cmp.Diff(ref, objectRefOf(u)); diff != "" {
+		t.Errorf("ToUnstructured(): -want reference, +got reference:\n%s", diff)
+	}
+	if diff := cmp.Diff(uid, u.GetUID()); diff != "" {
+		t.Errorf("ToUnstructured(): -want UID, +got UID:\n%s", diff)
+	}
+
+	s = PatchPending(ref, nil)
+	u = s.ToUnstructured()
+	if diff := cmp.Diff(types.UID(""), u.GetUID()); diff != "" {
+		t.Errorf("ToUnstructured(): an unknown UID should not be set: -want UID, +got UID:\n%s", diff)
+	}
+}
@@ -411,6 +411,11 @@ func (in *InControlPlaneOverrideSpec) DeepCopyInto(out *InControlPlaneOverrideSp
 	*out = *in
 	in.TargetRef.DeepCopyInto(&out.TargetRef)
 	in.Override.DeepCopyInto(&out.Override)
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InControlPlaneOverrideSpec.
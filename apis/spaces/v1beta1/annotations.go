@@ -0,0 +1,174 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// KubeCompositionK8s is the name of the default KubeControlPlane
+	// composition.
+	KubeCompositionK8s = "k8s"
+	// KubeCompositionKine is the name of the KubeControlPlane composition
+	// backed by kine rather than etcd.
+	KubeCompositionKine = "kine"
+
+	// InternalAnnotationPrefix is the prefix of the annotations that are
+	// managed by Spaces rather than by users.
+	InternalAnnotationPrefix = "internal.spaces.upbound.io/"
+)
+
+const (
+	errFmtInvalidFeatures        = "invalid %s annotation: must be a JSON object mapping feature names to booleans"
+	errFmtInvalidTierLimits      = "invalid %s annotation: must be a JSON object"
+	errFmtUnknownKubeComposition = "unknown kube composition %q: must be one of %s"
+)
+
+// DefaultKubeCompositionRegistry is the KubeCompositionRegistry consulted by
+// the package level helpers. It knows the compositions supported by Spaces;
+// additional compositions can be registered with RegisterKubeComposition.
+var DefaultKubeCompositionRegistry = NewKubeCompositionRegistry(KubeCompositionK8s, KubeCompositionKine)
+
+// KubeCompositionRegistry keeps track of the default and the known
+// KubeControlPlane compositions that can be selected with the
+// KubeCompositionAnnotation.
+// +kubebuilder:object:generate=false
+type KubeCompositionRegistry struct {
+	mu    sync.RWMutex
+	def   string
+	known map[string]struct{}
+}
+
+// NewKubeCompositionRegistry returns a KubeCompositionRegistry with the
+// supplied default composition. The default composition and the supplied
+// additional compositions are registered as known compositions.
+func NewKubeCompositionRegistry(def string, known ...string) *KubeCompositionRegistry {
+	r := &KubeCompositionRegistry{
+		def:   def,
+		known: map[string]struct{}{def: {}},
+	}
+	r.Register(known...)
+	return r
+}
+
+// Default returns the default composition.
+func (r *KubeCompositionRegistry) Default() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.def
+}
+
+// Register registers the supplied compositions as known compositions.
+func (r *KubeCompositionRegistry) Register(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range names {
+		r.known[n] = struct{}{}
+	}
+}
+
+// IsKnown returns true if the supplied composition has been registered.
+func (r *KubeCompositionRegistry) IsKnown(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.known[name]
+	return ok
+}
+
+// Known returns the sorted names of the registered compositions.
+func (r *KubeCompositionRegistry) Known() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.known))
+	for n := range r.known {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultKubeComposition returns the composition selected for a ControlPlane
+// that does not have the KubeCompositionAnnotation.
+func DefaultKubeComposition() string {
+	return DefaultKubeCompositionRegistry.Default()
+}
+
+// RegisterKubeComposition registers additional known compositions with the
+// DefaultKubeCompositionRegistry.
+func RegisterKubeComposition(names ...string) {
+	DefaultKubeCompositionRegistry.Register(names...)
+}
+
+// KubeComposition returns the KubeControlPlane composition selected for this
+// ControlPlane, falling back to the default composition if the
+// KubeCompositionAnnotation is not set.
+func (mg *ControlPlane) KubeComposition() string {
+	if c := mg.GetAnnotations()[KubeCompositionAnnotation]; c != "" {
+		return c
+	}
+	return DefaultKubeComposition()
+}
+
+// MergeAnnotations returns the desired annotations of a ControlPlane together
+// with the internal annotations in existing that desired does not include.
+// Internal annotations, i.e., the ones prefixed with InternalAnnotationPrefix,
+// are managed by Spaces and are only overwritten if explicitly included in
+// desired. Any other existing annotation not in desired is dropped.
+func MergeAnnotations(existing, desired map[string]string) map[string]string {
+	merged := make(map[string]string, len(desired))
+	for k, v := range existing {
+		if strings.HasPrefix(k, InternalAnnotationPrefix) {
+			merged[k] = v
+		}
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
+
+// ValidateAnnotations validates the internal annotations of this ControlPlane
+// all at once: the FeaturesAnnotation and the TierLimitsAnnotation must hold
+// JSON objects and the KubeCompositionAnnotation must select a composition
+// known to the DefaultKubeCompositionRegistry. All errors found are aggregated
+// rather than returning the first one.
+// Annotations that are not set are not validated. An empty
+// KubeCompositionAnnotation selects the default composition, as it does for
+// KubeComposition, and is therefore valid.
+func (mg *ControlPlane) ValidateAnnotations() error {
+	a := mg.GetAnnotations()
+	var errs []error
+	if v, ok := a[FeaturesAnnotation]; ok {
+		if err := json.Unmarshal([]byte(v), &map[string]bool{}); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtInvalidFeatures, FeaturesAnnotation))
+		}
+	}
+	if v, ok := a[TierLimitsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(v), &map[string]any{}); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtInvalidTierLimits, TierLimitsAnnotation))
+		}
+	}
+	if v, ok := a[KubeCompositionAnnotation]; ok && v != "" && !DefaultKubeCompositionRegistry.IsKnown(v) {
+		errs = append(errs, errors.Errorf(errFmtUnknownKubeComposition, v, strings.Join(DefaultKubeCompositionRegistry.Known(), ", ")))
+	}
+	return kerrors.NewAggregate(errs)
+}
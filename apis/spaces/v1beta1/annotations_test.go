@@ -0,0 +1,178 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func TestKubeCompositionRegistry(t *testing.T) {
+	r := NewKubeCompositionRegistry("k8s", "vcluster")
+	if diff := cmp.Diff("k8s", r.Default()); diff != "" {
+		t.Errorf("Default(): -want, +got:\n%s", diff)
+	}
+	if r.IsKnown("kine") {
+		t.Errorf("IsKnown(%q): want false before registration", "kine")
+	}
+	r.Register("kine")
+	if diff := cmp.Diff([]string{"k8s", "kine", "vcluster"}, r.Known()); diff != "" {
+		t.Errorf("Known(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestKubeComposition(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        string
+	}{
+		"Default": {
+			reason: "A ControlPlane without the annotation should use the default composition.",
+			want:   KubeCompositionK8s,
+		},
+		"EmptyAnnotation": {
+			reason:      "A ControlPlane with an empty annotation should use the default composition.",
+			annotations: map[string]string{KubeCompositionAnnotation: ""},
+			want:        KubeCompositionK8s,
+		},
+		"Annotated": {
+			reason:      "A ControlPlane with the annotation should use the annotated composition.",
+			annotations: map[string]string{KubeCompositionAnnotation: "kine"},
+			want:        "kine",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctp := &ControlPlane{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if diff := cmp.Diff(tc.want, ctp.KubeComposition()); diff != "" {
+				t.Errorf("\n%s\nKubeComposition(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMergeAnnotations(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		existing map[string]string
+		desired  map[string]string
+		want     map[string]string
+	}{
+		"Empty": {
+			reason: "Merging empty annotations should yield empty annotations.",
+			want:   map[string]string{},
+		},
+		"DesiredOmitsInternal": {
+			reason: "Internal annotations omitted from desired should be preserved, whereas other omitted annotations should be dropped.",
+			existing: map[string]string{
+				KubeCompositionAnnotation: "k8s",
+				FeaturesAnnotation:        "enableKine",
+				"example.org/owner":       "team-a",
+			},
+			desired: map[string]string{
+				"example.org/cost-center": "1234",
+			},
+			want: map[string]string{
+				KubeCompositionAnnotation: "k8s",
+				FeaturesAnnotation:        "enableKine",
+				"example.org/cost-center": "1234",
+			},
+		},
+		"DesiredIncludesInternal": {
+			reason: "Internal annotations explicitly included in desired should be overwritten.",
+			existing: map[string]string{
+				KubeCompositionAnnotation: "k8s",
+			},
+			desired: map[string]string{
+				KubeCompositionAnnotation: "vcluster",
+			},
+			want: map[string]string{
+				KubeCompositionAnnotation: "vcluster",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MergeAnnotations(tc.existing, tc.desired)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMergeAnnotations(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        error
+	}{
+		"NoAnnotations": {
+			reason: "A ControlPlane without annotations should be valid.",
+		},
+		"Valid": {
+			reason: "A ControlPlane with valid internal annotations should be valid.",
+			annotations: map[string]string{
+				FeaturesAnnotation:        `{"featureA": true, "featureB": false}`,
+				TierLimitsAnnotation:      `{"maxResources": 1000}`,
+				KubeCompositionAnnotation: KubeCompositionK8s,
+				"example.org/other":       "not validated",
+			},
+		},
+		"KineComposition": {
+			reason:      "The kine composition is supported by Spaces and should be valid.",
+			annotations: map[string]string{KubeCompositionAnnotation: KubeCompositionKine},
+		},
+		"EmptyComposition": {
+			reason:      "An empty kube composition annotation selects the default composition and should be valid.",
+			annotations: map[string]string{KubeCompositionAnnotation: ""},
+		},
+		"InvalidFeatures": {
+			reason:      "A features annotation that does not map feature names to booleans should be invalid.",
+			annotations: map[string]string{FeaturesAnnotation: `{"featureA": "yes"}`},
+			want: kerrors.NewAggregate([]error{
+				errors.Wrapf(errors.New("json: cannot unmarshal string into Go struct field .featureA of type bool"), errFmtInvalidFeatures, FeaturesAnnotation),
+			}),
+		},
+		"MultipleInvalid": {
+			reason: "All invalid annotations should be reported at once.",
+			annotations: map[string]string{
+				FeaturesAnnotation:        "enableKine",
+				TierLimitsAnnotation:      "[]",
+				KubeCompositionAnnotation: "unsupported",
+			},
+			want: kerrors.NewAggregate([]error{
+				errors.Wrapf(errors.New("invalid character 'e' looking for beginning of value"), errFmtInvalidFeatures, FeaturesAnnotation),
+				errors.Wrapf(errors.New("json: cannot unmarshal array into Go value of type map[string]interface {}"), errFmtInvalidTierLimits, TierLimitsAnnotation),
+				errors.Errorf(errFmtUnknownKubeComposition, "unsupported", "k8s, kine"),
+			}),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			err := mg.ValidateAnnotations()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateAnnotations(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
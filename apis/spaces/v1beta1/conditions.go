@@ -4,7 +4,7 @@
 package v1beta1
 
 import (
-	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -67,6 +67,14 @@ const (
 	ReasonStarted xpcommonv1.ConditionReason = "Started"
 )
 
+// The messages of the SourceSynced conditions embed the source revision and
+// are parsed by ExtractSyncedRevision. They are an internal contract and must
+// only be changed together with ExtractSyncedRevision.
+const (
+	msgPrefixSourceSynced     = "In sync with the revision "
+	msgPrefixSourceInProgress = "Syncing revision "
+)
+
 // Healthy returns a condition that indicates the control plane is healthy.
 func Healthy() xpcommonv1.Condition {
 	return xpcommonv1.Condition{
@@ -129,7 +137,7 @@ func SourceSynced(revision string) xpcommonv1.Condition {
 		Status:             corev1.ConditionTrue,
 		LastTransitionTime: metav1.Now(),
 		Reason:             ReasonSourceCompleted,
-		Message:            fmt.Sprintf("In sync with the revision %s", revision),
+		Message:            msgPrefixSourceSynced + revision,
 	}
 }
 
@@ -141,7 +149,7 @@ func SourceInProgress(revision string) xpcommonv1.Condition {
 		Status:             corev1.ConditionFalse,
 		LastTransitionTime: metav1.Now(),
 		Reason:             ReasonSourceInProgress,
-		Message:            fmt.Sprintf("Syncing revision %s", revision),
+		Message:            msgPrefixSourceInProgress + revision,
 	}
 }
 
@@ -157,6 +165,30 @@ func SourceError(err error) xpcommonv1.Condition {
 	}
 }
 
+// ExtractSyncedRevision returns the source revision embedded in the message of
+// a SourceSynced condition, which is either the revision the control plane is
+// in sync with or the revision being synced. It returns false if the condition
+// is not a SourceSynced condition or if its message does not carry a revision,
+// e.g., because the source operation has failed.
+func ExtractSyncedRevision(cond xpcommonv1.Condition) (string, bool) {
+	if cond.Type != ConditionTypeSourceSynced {
+		return "", false
+	}
+	msg := strings.TrimSpace(cond.Message)
+	for _, prefix := range []string{msgPrefixSourceSynced, msgPrefixSourceInProgress} {
+		rev, ok := strings.CutPrefix(msg, prefix)
+		if !ok {
+			continue
+		}
+		rev = strings.TrimSpace(rev)
+		if rev == "" || strings.ContainsAny(rev, " \t\n") {
+			return "", false
+		}
+		return rev, true
+	}
+	return "", false
+}
+
 // SupportedCrossplaneVersion returns a condition that indicates that the
 // control plane is running a supported version of Crossplane.
 func SupportedCrossplaneVersion() xpcommonv1.Condition {
@@ -262,3 +294,86 @@ func StartCompleted() xpcommonv1.Condition {
 		Reason:             ReasonStarted,
 	}
 }
+
+// FailingConditions returns the supplied conditions whose status is False,
+// indicating that something is wrong. All ControlPlane condition types have
+// a positive polarity, i.e., True is their healthy state, with one exception:
+// the CrossplaneRunning condition is expected to be False while the
+// Crossplane and provider workloads are being paused or have been paused on
+// purpose, so it is not reported as failing with the Pausing and Paused
+// reasons.
+func FailingConditions(conds []xpcommonv1.Condition) []xpcommonv1.Condition {
+	var failing []xpcommonv1.Condition
+	for _, c := range conds {
+		if c.Status != corev1.ConditionFalse {
+			continue
+		}
+		if c.Type == ConditionTypeRunning && (c.Reason == ReasonPaused || c.Reason == ReasonPausing) {
+			continue
+		}
+		failing = append(failing, c)
+	}
+	return failing
+}
+
+// conditionFailure identifies a ControlPlane failure by the type and the
+// reason of the condition reporting it.
+type conditionFailure struct {
+	Type   xpcommonv1.ConditionType
+	Reason xpcommonv1.ConditionReason
+}
+
+// conditionSeverity lists the ControlPlane failures reported by
+// MostSevereCondition, from the most to the least severe.
+var conditionSeverity = []conditionFailure{
+	{Type: ConditionTypeControlPlaneProvisioned, Reason: ReasonProvisioningError},
+	{Type: ConditionTypeSupported, Reason: ReasonUnsupported},
+	{Type: ConditionTypeHealthy, Reason: ReasonUnhealthy},
+	{Type: ConditionTypeRestored, Reason: ReasonRestoreFailed},
+}
+
+// MostSevereCondition returns the condition reporting the most severe failure
+// of this ControlPlane, to be surfaced as a single status indicator. Failures
+// are identified by the reasons of the failing conditions, as determined by
+// FailingConditions, so that conditions that are False while an operation is
+// in progress, e.g., while the ControlPlane is being provisioned, are not
+// reported. The severity ordering, from the most severe, is:
+//
+//  1. A provisioning error, i.e., the ProvisioningError reason.
+//  2. An unsupported Crossplane version, i.e., the
+//     UnsupportedCrossplaneVersion reason.
+//  3. An unhealthy control plane, i.e., the UnhealthyControlPlane reason.
+//  4. A failed restore from a backup, i.e., the Failed reason of the
+//     Restored condition.
+//
+// It returns false if none of these failures is reported.
+func (mg *ControlPlane) MostSevereCondition() (xpcommonv1.Condition, bool) {
+	failing := FailingConditions(mg.Status.Conditions)
+	for _, f := range conditionSeverity {
+		for _, c := range failing {
+			if c.Type == f.Type && c.Reason == f.Reason {
+				return c, true
+			}
+		}
+	}
+	return xpcommonv1.Condition{}, false
+}
+
+// ReasonChanges returns the conditions in newConds whose reason differs from
+// the reason of the condition of the same type in oldConds, regardless of
+// whether their status has changed, so that transitions such as Provisioning
+// to ProvisioningError can be reported as events. Conditions whose type is
+// not present in oldConds are not considered as reason changes.
+func ReasonChanges(oldConds, newConds []xpcommonv1.Condition) []xpcommonv1.Condition {
+	reasons := make(map[xpcommonv1.ConditionType]xpcommonv1.ConditionReason, len(oldConds))
+	for _, c := range oldConds {
+		reasons[c.Type] = c.Reason
+	}
+	var changed []xpcommonv1.Condition
+	for _, c := range newConds {
+		if r, ok := reasons[c.Type]; ok && r != c.Reason {
+			changed = append(changed, c)
+		}
+	}
+	return changed
+}
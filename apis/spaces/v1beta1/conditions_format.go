@@ -0,0 +1,86 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// conditionOrder is the order in which the known condition types are
+// rendered by FormatConditions. Conditions that are prerequisites of others
+// come first, e.g., a control plane needs to be provisioned before it can be
+// restored or become healthy, and the aggregate Synced and Ready conditions
+// come last.
+var conditionOrder = []xpcommonv1.ConditionType{
+	ConditionTypeControlPlaneProvisioned,
+	ConditionTypeRestored,
+	ConditionTypeSupported,
+	ConditionTypeRunning,
+	ConditionTypeHealthy,
+	ConditionTypeSourceSynced,
+	xpcommonv1.TypeSynced,
+	xpcommonv1.TypeReady,
+}
+
+// FormatConditions renders the supplied conditions as a table with aligned
+// type, status, reason, message and age columns, suitable for describe-style
+// command line output. The known condition types are rendered in the order
+// of ControlPlaneProvisioned, Restored, Supported, CrossplaneRunning, Healthy,
+// SourceSynced, Synced and Ready, followed by any other condition types in
+// lexical order.
+func FormatConditions(conds []xpcommonv1.Condition) string {
+	return formatConditions(conds, time.Now())
+}
+
+func formatConditions(conds []xpcommonv1.Condition, now time.Time) string {
+	rank := make(map[xpcommonv1.ConditionType]int, len(conditionOrder))
+	for i, t := range conditionOrder {
+		rank[t] = i
+	}
+	sorted := make([]xpcommonv1.Condition, len(conds))
+	copy(sorted, conds)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, iok := rank[sorted[i].Type]
+		rj, jok := rank[sorted[j].Type]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		default:
+			return sorted[i].Type < sorted[j].Type
+		}
+	})
+
+	b := &strings.Builder{}
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tSTATUS\tREASON\tMESSAGE\tAGE")
+	for _, c := range sorted {
+		age := "<unknown>"
+		if !c.LastTransitionTime.IsZero() {
+			age = duration.HumanDuration(now.Sub(c.LastTransitionTime.Time))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message, age)
+	}
+	_ = w.Flush()
+	return b.String()
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const goldenConditions = `TYPE                     STATUS  REASON                 MESSAGE                                                AGE
+ControlPlaneProvisioned  True    Provisioned                                                                   2d
+Restored                 True    Completed              Control plane has been restored from specified backup  47h
+Healthy                  False   UnhealthyControlPlane                                                         5m
+Ready                    True    Available                                                                     90s
+Custom                   True    Something                                                                     <unknown>
+`
+
+func TestFormatConditions(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(-d)) }
+
+	conds := []xpcommonv1.Condition{
+		{Type: xpcommonv1.TypeReady, Status: corev1.ConditionTrue, Reason: xpcommonv1.ReasonAvailable, LastTransitionTime: at(90 * time.Second)},
+		{Type: "Custom", Status: corev1.ConditionTrue, Reason: "Something"},
+		{Type: ConditionTypeHealthy, Status: corev1.ConditionFalse, Reason: ReasonUnhealthy, LastTransitionTime: at(5 * time.Minute)},
+		{Type: ConditionTypeRestored, Status: corev1.ConditionTrue, Reason: ReasonRestoreCompleted, Message: "Control plane has been restored from specified backup", LastTransitionTime: at(47 * time.Hour)},
+		{Type: ConditionTypeControlPlaneProvisioned, Status: corev1.ConditionTrue, Reason: ReasonProvisioned, LastTransitionTime: at(48 * time.Hour)},
+	}
+	if diff := cmp.Diff(goldenConditions, formatConditions(conds, now)); diff != "" {
+		t.Errorf("formatConditions(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestExtractSyncedRevision(t *testing.T) {
+	type want struct {
+		revision string
+		ok       bool
+	}
+	cases := map[string]struct {
+		reason string
+		cond   xpcommonv1.Condition
+		want   want
+	}{
+		"Synced": {
+			reason: "The revision should be extracted from a synced condition.",
+			cond:   SourceSynced("main@sha1:4f2a3c1"),
+			want:   want{revision: "main@sha1:4f2a3c1", ok: true},
+		},
+		"InProgress": {
+			reason: "The revision should be extracted from an in-progress condition.",
+			cond:   SourceInProgress("main@sha1:4f2a3c1"),
+			want:   want{revision: "main@sha1:4f2a3c1", ok: true},
+		},
+		"SurroundingWhitespace": {
+			reason: "Surrounding whitespace should be ignored.",
+			cond: xpcommonv1.Condition{
+				Type:    ConditionTypeSourceSynced,
+				Message: "  In sync with the revision 4f2a3c1 \n",
+			},
+			want: want{revision: "4f2a3c1", ok: true},
+		},
+		"Error": {
+			reason: "No revision should be extracted from an error condition.",
+			cond:   SourceError(errors.New("boom")),
+		},
+		"EmptyRevision": {
+			reason: "No revision should be extracted from a message without a revision.",
+			cond:   SourceSynced(""),
+		},
+		"UnexpectedFormat": {
+			reason: "No revision should be extracted from a message in an unexpected format.",
+			cond: xpcommonv1.Condition{
+				Type:    ConditionTypeSourceSynced,
+				Message: "Syncing revision 4f2a3c1 from the source",
+			},
+		},
+		"OtherConditionType": {
+			reason: "No revision should be extracted from a condition that is not a SourceSynced condition.",
+			cond: xpcommonv1.Condition{
+				Type:    ConditionTypeHealthy,
+				Message: "In sync with the revision 4f2a3c1",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			revision, ok := ExtractSyncedRevision(tc.cond)
+			if diff := cmp.Diff(tc.want, want{revision: revision, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nExtractSyncedRevision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFailingConditions(t *testing.T) {
+	unhealthy := Unhealthy()
+	restoreFailed := RestoreFailed(errors.New("boom"))
+	starting := StartInProgress()
+	unavailable := xpcommonv1.Unavailable()
+
+	cases := map[string]struct {
+		reason string
+		conds  []xpcommonv1.Condition
+		want   []xpcommonv1.Condition
+	}{
+		"None": {
+			reason: "No conditions should be failing if there are no conditions.",
+		},
+		"Healthy": {
+			reason: "No conditions should be failing if all conditions are True.",
+			conds:  []xpcommonv1.Condition{Healthy(), ControlPlaneProvisioned(), xpcommonv1.Available()},
+		},
+		"MixedPolarity": {
+			reason: "False conditions should be failing, except for a CrossplaneRunning condition that is False because of a pause.",
+			conds: []xpcommonv1.Condition{
+				Healthy(),
+				unhealthy,
+				PauseCompleted(),
+				restoreFailed,
+				unavailable,
+				{Type: ConditionTypeSupported, Status: corev1.ConditionUnknown},
+			},
+			want: []xpcommonv1.Condition{unhealthy, restoreFailed, unavailable},
+		},
+		"Pausing": {
+			reason: "A CrossplaneRunning condition that is False while pausing should not be failing.",
+			conds:  []xpcommonv1.Condition{PauseInProgress()},
+		},
+		"Starting": {
+			reason: "A CrossplaneRunning condition that is False while starting should be failing.",
+			conds:  []xpcommonv1.Condition{starting},
+			want:   []xpcommonv1.Condition{starting},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := FailingConditions(tc.conds)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFailingConditions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMostSevereCondition(t *testing.T) {
+	provisioningError := ControlPlaneProvisioningError(errors.New("boom"))
+	unsupported := UnsupportedCrossplaneVersion("1.12 is no longer supported")
+	unhealthy := Unhealthy()
+	restoreFailed := RestoreFailed(errors.New("boom"))
+
+	type want struct {
+		cond xpcommonv1.Condition
+		ok   bool
+	}
+	cases := map[string]struct {
+		reason string
+		conds  []xpcommonv1.Condition
+		want   want
+	}{
+		"NoConditions": {
+			reason: "A ControlPlane without conditions should have no severe condition.",
+		},
+		"Healthy": {
+			reason: "A healthy ControlPlane should have no severe condition.",
+			conds:  []xpcommonv1.Condition{Healthy(), ControlPlaneProvisioned(), SupportedCrossplaneVersion()},
+		},
+		"OtherFailing": {
+			reason: "Failing conditions without a severity, such as Ready, should not be reported.",
+			conds:  []xpcommonv1.Condition{xpcommonv1.Unavailable(), PauseCompleted()},
+		},
+		"InProgress": {
+			reason: "A ControlPlane that is being provisioned should have no severe condition.",
+			conds:  []xpcommonv1.Condition{ControlPlaneProvisionInProgress(), RestorePending()},
+		},
+		"OtherReasons": {
+			reason: "False conditions with reasons other than the failure reasons should not be reported.",
+			conds: []xpcommonv1.Condition{
+				{Type: ConditionTypeHealthy, Status: corev1.ConditionFalse, Reason: "Starting"},
+				{Type: ConditionTypeRestored, Status: corev1.ConditionFalse, Reason: "InProgress"},
+			},
+		},
+		"ProvisioningErrorFirst": {
+			reason: "A provisioning error should be more severe than any other failing condition.",
+			conds:  []xpcommonv1.Condition{restoreFailed, unhealthy, unsupported, provisioningError},
+			want:   want{cond: provisioningError, ok: true},
+		},
+		"UnsupportedOverUnhealthy": {
+			reason: "An unsupported version should be more severe than an unhealthy control plane.",
+			conds:  []xpcommonv1.Condition{restoreFailed, unhealthy, unsupported, ControlPlaneProvisioned()},
+			want:   want{cond: unsupported, ok: true},
+		},
+		"UnhealthyOverRestoreFailed": {
+			reason: "An unhealthy control plane should be more severe than a failed restore.",
+			conds:  []xpcommonv1.Condition{restoreFailed, unhealthy},
+			want:   want{cond: unhealthy, ok: true},
+		},
+		"RestoreFailed": {
+			reason: "A failed restore should be reported if it is the only failing condition.",
+			conds:  []xpcommonv1.Condition{Healthy(), restoreFailed, xpcommonv1.Unavailable()},
+			want:   want{cond: restoreFailed, ok: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{}
+			mg.SetConditions(tc.conds...)
+			cond, ok := mg.MostSevereCondition()
+			if diff := cmp.Diff(tc.want, want{cond: cond, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nMostSevereCondition(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReasonChanges(t *testing.T) {
+	provisioningError := ControlPlaneProvisioningError(errors.New("boom"))
+	provisioningFailed := provisioningError
+	provisioningFailed.Reason = "ProvisioningFailed"
+	pauseCompleted := PauseCompleted()
+
+	cases := map[string]struct {
+		reason string
+		old    []xpcommonv1.Condition
+		new    []xpcommonv1.Condition
+		want   []xpcommonv1.Condition
+	}{
+		"None": {
+			reason: "There should be no reason changes if there are no conditions.",
+		},
+		"Unchanged": {
+			reason: "Conditions with the same reasons should not be reported.",
+			old:    []xpcommonv1.Condition{Healthy(), ControlPlaneProvisioned()},
+			new:    []xpcommonv1.Condition{Healthy(), ControlPlaneProvisioned()},
+		},
+		"SameStatusDifferentReason": {
+			reason: "A condition whose reason changed while its status stayed the same should be reported.",
+			old:    []xpcommonv1.Condition{ControlPlaneProvisionInProgress()},
+			new:    []xpcommonv1.Condition{provisioningError},
+			want:   []xpcommonv1.Condition{provisioningError},
+		},
+		"SameStatusSameReasonDifferentMessage": {
+			reason: "A condition whose message changed but whose reason did not should not be reported.",
+			old:    []xpcommonv1.Condition{provisioningError},
+			new:    []xpcommonv1.Condition{ControlPlaneProvisioningError(errors.New("bang"))},
+		},
+		"DifferentStatusDifferentReason": {
+			reason: "A condition whose status and reason changed should be reported.",
+			old:    []xpcommonv1.Condition{StartCompleted(), provisioningFailed},
+			new:    []xpcommonv1.Condition{pauseCompleted, provisioningError},
+			want:   []xpcommonv1.Condition{pauseCompleted, provisioningError},
+		},
+		"NewType": {
+			reason: "A condition whose type was not present before should not be reported.",
+			old:    []xpcommonv1.Condition{Healthy()},
+			new:    []xpcommonv1.Condition{Healthy(), ControlPlaneProvisioned()},
+		},
+		"RemovedType": {
+			reason: "A condition that was removed should not be reported.",
+			old:    []xpcommonv1.Condition{Healthy(), ControlPlaneProvisioned()},
+			new:    []xpcommonv1.Condition{Healthy()},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ReasonChanges(tc.old, tc.new)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReasonChanges(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
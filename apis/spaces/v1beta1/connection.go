@@ -0,0 +1,68 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// connectionSecretNamePrefix is the prefix of the name of the secret the
+	// connection details of a ControlPlane are written to by default.
+	connectionSecretNamePrefix = "kubeconfig-"
+)
+
+// DefaultConnectionSecretName returns the name of the secret the connection
+// details of this ControlPlane are written to when its
+// WriteConnectionSecretToReference is not set. By convention, the secret is
+// named "kubeconfig-<control plane name>" and lives in the namespace of the
+// ControlPlane.
+func (mg *ControlPlane) DefaultConnectionSecretName() string {
+	return connectionSecretNamePrefix + mg.GetName()
+}
+
+// PublishesConnectionDetails returns true if this ControlPlane writes its
+// connection details to a secret, i.e., if ResolvedConnectionSecretRef
+// resolves a secret. This is the case if its WriteConnectionSecretToReference
+// is set, or otherwise if it has a name to derive the
+// DefaultConnectionSecretName from.
+func (mg *ControlPlane) PublishesConnectionDetails() bool {
+	if mg == nil {
+		return false
+	}
+	_, ok := mg.ResolvedConnectionSecretRef()
+	return ok
+}
+
+// ResolvedConnectionSecretRef returns the name and namespace of the secret the
+// connection details of this ControlPlane are written to. The secret
+// referenced by WriteConnectionSecretToReference defaults to the namespace of
+// the ControlPlane, and the DefaultConnectionSecretName is used if no secret
+// is referenced. It returns false if no secret can be resolved, i.e., if
+// neither a secret is referenced nor the ControlPlane has a name to derive
+// the default secret name from.
+func (mg *ControlPlane) ResolvedConnectionSecretRef() (types.NamespacedName, bool) {
+	if ref := mg.Spec.WriteConnectionSecretToReference; ref != nil && ref.Name != "" {
+		ns := ref.Namespace
+		if ns == "" {
+			ns = mg.GetNamespace()
+		}
+		return types.NamespacedName{Namespace: ns, Name: ref.Name}, true
+	}
+	if mg.GetName() == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.DefaultConnectionSecretName()}, true
+}
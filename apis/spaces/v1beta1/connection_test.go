@@ -0,0 +1,135 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDefaultConnectionSecretName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ctp    *ControlPlane
+		want   string
+	}{
+		"Default": {
+			reason: "The default connection secret should be named after the ControlPlane.",
+			ctp:    &ControlPlane{ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"}},
+			want:   "kubeconfig-ctp",
+		},
+		"ExplicitReference": {
+			reason: "The default connection secret name should not depend on an explicit reference.",
+			ctp: &ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"},
+				Spec: ControlPlaneSpec{
+					WriteConnectionSecretToReference: &SecretReference{Name: "custom"},
+				},
+			},
+			want: "kubeconfig-ctp",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.ctp.DefaultConnectionSecretName()); diff != "" {
+				t.Errorf("\n%s\nDefaultConnectionSecretName(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPublishesConnectionDetails(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ctp    *ControlPlane
+		want   bool
+	}{
+		"Nil": {
+			reason: "A nil ControlPlane should not publish connection details.",
+		},
+		"NoReference": {
+			reason: "A ControlPlane without a connection secret reference should publish connection details to the default secret.",
+			ctp:    &ControlPlane{ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"}},
+			want:   true,
+		},
+		"NoReferenceNoName": {
+			reason: "A ControlPlane without a connection secret reference and a name should not publish connection details.",
+			ctp:    &ControlPlane{},
+		},
+		"Reference": {
+			reason: "A ControlPlane with a connection secret reference should publish connection details.",
+			ctp: &ControlPlane{Spec: ControlPlaneSpec{
+				WriteConnectionSecretToReference: &SecretReference{Name: "kubeconfig-ctp", Namespace: "default"},
+			}},
+			want: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.ctp.PublishesConnectionDetails()); diff != "" {
+				t.Errorf("\n%s\nPublishesConnectionDetails(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolvedConnectionSecretRef(t *testing.T) {
+	type want struct {
+		ref types.NamespacedName
+		ok  bool
+	}
+	cases := map[string]struct {
+		reason string
+		ctp    *ControlPlane
+		want   want
+	}{
+		"Default": {
+			reason: "The default connection secret should be resolved if no secret is referenced.",
+			ctp:    &ControlPlane{ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"}},
+			want:   want{ref: types.NamespacedName{Namespace: "default", Name: "kubeconfig-ctp"}, ok: true},
+		},
+		"ReferenceWithoutNamespace": {
+			reason: "A referenced secret without a namespace should be resolved in the namespace of the ControlPlane.",
+			ctp: &ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"},
+				Spec:       ControlPlaneSpec{WriteConnectionSecretToReference: &SecretReference{Name: "custom"}},
+			},
+			want: want{ref: types.NamespacedName{Namespace: "default", Name: "custom"}, ok: true},
+		},
+		"ReferenceWithNamespace": {
+			reason: "A referenced secret with a namespace should be resolved in that namespace.",
+			ctp: &ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"},
+				Spec:       ControlPlaneSpec{WriteConnectionSecretToReference: &SecretReference{Name: "custom", Namespace: "secrets"}},
+			},
+			want: want{ref: types.NamespacedName{Namespace: "secrets", Name: "custom"}, ok: true},
+		},
+		"Unresolvable": {
+			reason: "No secret should be resolved for a ControlPlane without a name or a secret reference.",
+			ctp:    &ControlPlane{ObjectMeta: metav1.ObjectMeta{GenerateName: "ctp-", Namespace: "default"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ref, ok := tc.ctp.ResolvedConnectionSecretRef()
+			if diff := cmp.Diff(tc.want, want{ref: ref, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nResolvedConnectionSecretRef(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/upbound/up-sdk-go/apis/common"
+)
+
+// A ControlPlaneOption modifies a ControlPlane built by NewControlPlane.
+// +kubebuilder:object:generate=false
+type ControlPlaneOption func(mg *ControlPlane)
+
+// WithVersion pins the Crossplane version of the ControlPlane.
+func WithVersion(version string) ControlPlaneOption {
+	return func(mg *ControlPlane) {
+		mg.Spec.Crossplane.Version = ptr.To(version)
+	}
+}
+
+// WithChannel sets the Crossplane upgrade channel of the ControlPlane. The
+// None channel requires a version to be pinned using WithVersion.
+func WithChannel(c CrossplaneUpgradeChannel) ControlPlaneOption {
+	return func(mg *ControlPlane) {
+		if mg.Spec.Crossplane.AutoUpgradeSpec == nil {
+			mg.Spec.Crossplane.AutoUpgradeSpec = &CrossplaneAutoUpgradeSpec{}
+		}
+		mg.Spec.Crossplane.AutoUpgradeSpec.Channel = ptr.To(c)
+	}
+}
+
+// WithRestore configures the ControlPlane to be restored from the supplied
+// Backup or BackupSchedule.
+func WithRestore(source common.TypedLocalObjectReference) ControlPlaneOption {
+	return func(mg *ControlPlane) {
+		mg.Spec.Restore = &Restore{Source: source}
+	}
+}
+
+// NewControlPlane returns a ControlPlane with the supplied name and namespace,
+// modified by the supplied options. Without any options, the returned
+// ControlPlane uses the Stable upgrade channel and the Running state, as
+// defaulted by the API server.
+func NewControlPlane(name, namespace string, opts ...ControlPlaneOption) *ControlPlane {
+	mg := &ControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       ControlPlaneKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: ControlPlaneSpec{
+			Crossplane: CrossplaneSpec{
+				AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{
+					Channel: ptr.To(CrossplaneUpgradeStable),
+				},
+				State: ptr.To(CrossplaneStateRunning),
+			},
+		},
+	}
+	for _, o := range opts {
+		o(mg)
+	}
+	return mg
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// AgeBucketUnderHour is the age bucket of the ControlPlanes created less
+	// than an hour ago.
+	AgeBucketUnderHour = "under-1h"
+	// AgeBucketUnderDay is the age bucket of the ControlPlanes created at
+	// least an hour but less than a day ago.
+	AgeBucketUnderDay = "1h-1d"
+	// AgeBucketOverDay is the age bucket of the ControlPlanes created at least
+	// a day ago.
+	AgeBucketOverDay = "over-1d"
+)
+
+// ControlPlaneSummary is a view of a ControlPlane that is safe to return in
+// API responses. It deliberately omits any reference to the secrets holding
+// the connection details of the ControlPlane.
+// +kubebuilder:object:generate=false
+type ControlPlaneSummary struct {
+	// Name of the ControlPlane.
+	Name string `json:"name"`
+	// Group of the ControlPlane, i.e., its namespace.
+	Group string `json:"group"`
+	// Version of Crossplane requested for the ControlPlane.
+	Version string `json:"version,omitempty"`
+	// Channel is the effective Crossplane auto-upgrade channel of the
+	// ControlPlane, i.e., Stable unless a channel is specified.
+	Channel CrossplaneUpgradeChannel `json:"channel,omitempty"`
+	// Phase is the reason of the ControlPlane's Ready condition.
+	Phase string `json:"phase,omitempty"`
+	// Ready is true if the ControlPlane's Ready condition is True.
+	Ready bool `json:"ready"`
+}
+
+// Summary returns a ControlPlaneSummary of this ControlPlane.
+func (mg *ControlPlane) Summary() ControlPlaneSummary {
+	s := ControlPlaneSummary{
+		Name:    mg.GetName(),
+		Group:   mg.GetNamespace(),
+		Version: ptr.Deref(mg.Spec.Crossplane.Version, ""),
+		Channel: mg.Spec.Crossplane.EffectiveChannel(),
+	}
+	ready := mg.GetCondition(xpv1.TypeReady)
+	s.Phase = string(ready.Reason)
+	s.Ready = ready.Status == corev1.ConditionTrue
+	return s
+}
+
+// AgeBucket returns the age bucket of this ControlPlane at the supplied time,
+// based on its creation timestamp. The buckets are suitable as low
+// cardinality metric labels.
+func (mg *ControlPlane) AgeBucket(now time.Time) string {
+	age := now.Sub(mg.GetCreationTimestamp().Time)
+	switch {
+	case age < time.Hour:
+		return AgeBucketUnderHour
+	case age < 24*time.Hour:
+		return AgeBucketUnderDay
+	default:
+		return AgeBucketOverDay
+	}
+}
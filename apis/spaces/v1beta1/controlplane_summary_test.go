@@ -0,0 +1,141 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestSummary(t *testing.T) {
+	channel := CrossplaneUpgradeRapid
+
+	cases := map[string]struct {
+		reason string
+		ctp    *ControlPlane
+		want   ControlPlaneSummary
+	}{
+		"Empty": {
+			reason: "A ControlPlane without a spec or status should produce a summary with its name, group and the default Stable channel.",
+			ctp: &ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"},
+			},
+			want: ControlPlaneSummary{Name: "ctp", Group: "default", Channel: CrossplaneUpgradeStable},
+		},
+		"Ready": {
+			reason: "A ready ControlPlane should be summarized without its connection secret reference.",
+			ctp: &ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"},
+				Spec: ControlPlaneSpec{
+					WriteConnectionSecretToReference: &SecretReference{Name: "kubeconfig-ctp", Namespace: "default"},
+					Crossplane: CrossplaneSpec{
+						Version:         ptr.To("1.15.0-up.1"),
+						AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: &channel},
+					},
+				},
+				Status: ControlPlaneStatus{
+					ResourceStatus: xpv1.ResourceStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{xpv1.Available()},
+						},
+					},
+				},
+			},
+			want: ControlPlaneSummary{
+				Name:    "ctp",
+				Group:   "default",
+				Version: "1.15.0-up.1",
+				Channel: CrossplaneUpgradeRapid,
+				Phase:   string(xpv1.ReasonAvailable),
+				Ready:   true,
+			},
+		},
+		"RestorePending": {
+			reason: "A ControlPlane that is not ready should report the reason of its Ready condition as its phase.",
+			ctp: &ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"},
+				Status: ControlPlaneStatus{
+					ResourceStatus: xpv1.ResourceStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{
+							Conditions: []xpv1.Condition{RestorePending()},
+						},
+					},
+				},
+			},
+			want: ControlPlaneSummary{
+				Name:    "ctp",
+				Group:   "default",
+				Channel: CrossplaneUpgradeStable,
+				Phase:   string(ReasonRestorePending),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.ctp.Summary()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSummary(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAgeBucket(t *testing.T) {
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		age    time.Duration
+		want   string
+	}{
+		"JustCreated": {
+			reason: "A ControlPlane that has just been created should be in the under an hour bucket.",
+			want:   AgeBucketUnderHour,
+		},
+		"JustUnderHour": {
+			reason: "A ControlPlane created just under an hour ago should be in the under an hour bucket.",
+			age:    time.Hour - time.Second,
+			want:   AgeBucketUnderHour,
+		},
+		"Hour": {
+			reason: "A ControlPlane created exactly an hour ago should be in the hour to day bucket.",
+			age:    time.Hour,
+			want:   AgeBucketUnderDay,
+		},
+		"JustUnderDay": {
+			reason: "A ControlPlane created just under a day ago should be in the hour to day bucket.",
+			age:    24*time.Hour - time.Second,
+			want:   AgeBucketUnderDay,
+		},
+		"Day": {
+			reason: "A ControlPlane created exactly a day ago should be in the over a day bucket.",
+			age:    24 * time.Hour,
+			want:   AgeBucketOverDay,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+			if diff := cmp.Diff(tc.want, mg.AgeBucket(created.Add(tc.age))); diff != "" {
+				t.Errorf("\n%s\nAgeBucket(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
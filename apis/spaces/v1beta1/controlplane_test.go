@@ -0,0 +1,92 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	"github.com/upbound/up-sdk-go/apis/common"
+)
+
+func TestNewControlPlane(t *testing.T) {
+	source := common.TypedLocalObjectReference{Kind: "Backup", Name: "nightly"}
+
+	cases := map[string]struct {
+		reason string
+		opts   []ControlPlaneOption
+		want   ControlPlaneSpec
+	}{
+		"Defaults": {
+			reason: "Without options the ControlPlane should use the API server defaults.",
+			want: ControlPlaneSpec{
+				Crossplane: CrossplaneSpec{
+					AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeStable)},
+					State:           ptr.To(CrossplaneStateRunning),
+				},
+			},
+		},
+		"PinnedVersion": {
+			reason: "The version and channel options should pin the Crossplane version.",
+			opts:   []ControlPlaneOption{WithChannel(CrossplaneUpgradeNone), WithVersion("1.15.0-up.1")},
+			want: ControlPlaneSpec{
+				Crossplane: CrossplaneSpec{
+					Version:         ptr.To("1.15.0-up.1"),
+					AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeNone)},
+					State:           ptr.To(CrossplaneStateRunning),
+				},
+			},
+		},
+		"Restore": {
+			reason: "The restore option should set the restore source.",
+			opts:   []ControlPlaneOption{WithRestore(source)},
+			want: ControlPlaneSpec{
+				Crossplane: CrossplaneSpec{
+					AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeStable)},
+					State:           ptr.To(CrossplaneStateRunning),
+				},
+				Restore: &Restore{Source: source},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := NewControlPlane("ctp", "default", tc.opts...)
+			if diff := cmp.Diff(tc.want, mg.Spec); diff != "" {
+				t.Errorf("\n%s\nNewControlPlane(...): -want spec, +got spec:\n%s", tc.reason, diff)
+			}
+			if mg.GetName() != "ctp" || mg.GetNamespace() != "default" {
+				t.Errorf("\n%s\nNewControlPlane(...): want ctp in namespace default, got %s in namespace %s", tc.reason, mg.GetName(), mg.GetNamespace())
+			}
+			for _, err := range []error{
+				mg.ValidateName(),
+				mg.Spec.Crossplane.ValidateChannelVersionConsistency(),
+				mg.Spec.Crossplane.AutoUpgradeSpec.Validate(),
+				mg.Spec.Crossplane.State.Validate(),
+			} {
+				if err != nil {
+					t.Errorf("\n%s\nNewControlPlane(...): unexpected validation error: %v", tc.reason, err)
+				}
+			}
+			if r := mg.GetRestore(); r != nil {
+				if err := r.Validate(); err != nil {
+					t.Errorf("\n%s\nNewControlPlane(...): unexpected restore validation error: %v", tc.reason, err)
+				}
+			}
+		})
+	}
+}
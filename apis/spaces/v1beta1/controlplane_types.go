@@ -17,6 +17,7 @@ package v1beta1
 import (
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -65,7 +66,7 @@ const (
 const (
 	// KubeCompositionAnnotation is an optional, alpha-level annotation that
 	// selects the KubeControlPlane composition for a specific ControlPlane.
-	// The default value is "k8s".
+	// The default value is returned by DefaultKubeComposition.
 	//
 	// It is gated by the "EnableKine" feature gate.
 	KubeCompositionAnnotation = "internal.spaces.upbound.io/kube-composition"
@@ -130,6 +131,15 @@ type CrossplaneAutoUpgradeSpec struct {
 	// +kubebuilder:default="Stable"
 	// +kubebuilder:validation:Enum="None";"Patch";"Stable";"Rapid"
 	Channel *CrossplaneUpgradeChannel `json:"channel,omitempty"`
+
+	// VersionConstraint is an optional semantic version constraint, such as
+	// ">= 1.14, < 1.16", that restricts the Crossplane versions the control
+	// plane can be upgraded to. When set, only the versions satisfying the
+	// constraint are considered by the channel. Versions are matched on their
+	// release part, so that, e.g., 1.15.2-up.1 satisfies ">= 1.14, < 1.16".
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	VersionConstraint *string `json:"versionConstraint,omitempty"`
 }
 
 // CrossplaneSpec defines the configuration for Crossplane.
@@ -218,6 +228,35 @@ type ControlPlaneStatus struct {
 	// ControlPlane is in this condition.
 	Message        string `json:"message,omitempty"`
 	ControlPlaneID string `json:"controlPlaneID,omitempty"`
+
+	// ConditionHistory holds the most recent transitions of the ControlPlane's
+	// conditions, oldest first, so that flapping conditions can be observed.
+	// Only the last MaxConditionHistory transitions are kept.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	ConditionHistory []ConditionTransition `json:"conditionHistory,omitempty"`
+}
+
+// A ConditionTransition records a change of the status or the reason of a
+// ControlPlane condition.
+type ConditionTransition struct {
+	// Type of the condition that transitioned.
+	Type xpv1.ConditionType `json:"type"`
+
+	// FromStatus is the status of the condition before the transition. It
+	// is empty if the condition was not set before.
+	// +optional
+	FromStatus corev1.ConditionStatus `json:"fromStatus,omitempty"`
+
+	// ToStatus is the status of the condition after the transition.
+	ToStatus corev1.ConditionStatus `json:"toStatus"`
+
+	// Reason of the condition after the transition.
+	// +optional
+	Reason xpv1.ConditionReason `json:"reason,omitempty"`
+
+	// LastTransitionTime is the time of the transition.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,119 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	errFmtMarshalSpec = "cannot marshal the %s spec to YAML"
+)
+
+// DiffSpecYAML returns a unified diff of the YAML representations of the specs
+// of the supplied ControlPlanes, suitable for review. The specs are normalized
+// before diffing by applying the API server defaults, so that semantically
+// equal specs produce an empty diff. A nil ControlPlane, such as the old
+// ControlPlane on creation, is treated as an empty document.
+func DiffSpecYAML(oldCtp, newCtp *ControlPlane) (string, error) {
+	a, err := normalizedSpecYAML(oldCtp)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtMarshalSpec, "old")
+	}
+	b, err := normalizedSpecYAML(newCtp)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtMarshalSpec, "new")
+	}
+	return unifiedDiff(a, b), nil
+}
+
+// normalizedSpecYAML returns the lines of the YAML representation of the spec
+// of the supplied ControlPlane with the API server defaults applied.
+func normalizedSpecYAML(mg *ControlPlane) ([]string, error) {
+	if mg == nil {
+		return nil, nil
+	}
+	s := mg.Spec.DeepCopy()
+	c := s.Crossplane.EffectiveChannel()
+	if s.Crossplane.AutoUpgradeSpec == nil {
+		s.Crossplane.AutoUpgradeSpec = &CrossplaneAutoUpgradeSpec{}
+	}
+	s.Crossplane.AutoUpgradeSpec.Channel = &c
+	if s.Crossplane.State == nil {
+		s.Crossplane.State = ptr.To(CrossplaneStateRunning)
+	}
+	y, err := yaml.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(y), "\n"), "\n"), nil
+}
+
+// unifiedDiff returns a single hunk unified diff of the supplied lines with
+// full context, or an empty string if they are equal.
+func unifiedDiff(a, b []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+				continue
+			}
+			lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+		}
+	}
+	if lcs[0][0] == len(a) && len(a) == len(b) {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- old\n+++ new\n")
+	fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(len(a)), hunkRange(len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString(" " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("-" + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+" + b[j] + "\n")
+			j++
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange returns the range of a hunk of n lines starting at the first line
+// in the unified diff format.
+func hunkRange(n int) string {
+	if n == 0 {
+		return "0,0"
+	}
+	return fmt.Sprintf("1,%d", n)
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+)
+
+func TestDiffSpecYAML(t *testing.T) {
+	type args struct {
+		old *ControlPlane
+		new *ControlPlane
+	}
+	type want struct {
+		diff string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Equal": {
+			reason: "Equal specs should produce an empty diff.",
+			args: args{
+				old: NewControlPlane("ctp", "default", WithVersion("1.15.0-up.1")),
+				new: NewControlPlane("ctp", "default", WithVersion("1.15.0-up.1")),
+			},
+		},
+		"SemanticallyEqual": {
+			reason: "Specs that are equal once the API server defaults are applied should produce an empty diff.",
+			args: args{
+				old: &ControlPlane{},
+				new: NewControlPlane("ctp", "default"),
+			},
+		},
+		"MetadataOnly": {
+			reason: "Changes outside the spec should produce an empty diff.",
+			args: args{
+				old: NewControlPlane("ctp", "default"),
+				new: NewControlPlane("other", "default"),
+			},
+		},
+		"VersionChanged": {
+			reason: "A changed version should be shown as a removed and an added line.",
+			args: args{
+				old: NewControlPlane("ctp", "default", WithVersion("1.14.8-up.1")),
+				new: NewControlPlane("ctp", "default", WithVersion("1.15.0-up.1")),
+			},
+			want: want{
+				diff: `--- old
++++ new
+@@ -1,5 +1,5 @@
+ crossplane:
+   autoUpgrade:
+     channel: Stable
+   state: Running
+-  version: 1.14.8-up.1
++  version: 1.15.0-up.1
+`,
+			},
+		},
+		"Created": {
+			reason: "A ControlPlane without an old ControlPlane should be shown as all added lines.",
+			args: args{
+				new: &ControlPlane{Spec: ControlPlaneSpec{Crossplane: CrossplaneSpec{State: ptr.To(CrossplaneStatePaused)}}},
+			},
+			want: want{
+				diff: `--- old
++++ new
+@@ -0,0 +1,4 @@
++crossplane:
++  autoUpgrade:
++    channel: Stable
++  state: Paused
+`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			diff, err := DiffSpecYAML(tc.args.old, tc.args.new)
+			if d := cmp.Diff(tc.want.err, err, test.EquateErrors()); d != "" {
+				t.Errorf("\n%s\nDiffSpecYAML(...): -want error, +got error:\n%s", tc.reason, d)
+			}
+			if d := cmp.Diff(tc.want.diff, diff); d != "" {
+				t.Errorf("\n%s\nDiffSpecYAML(...): -want, +got:\n%s", tc.reason, d)
+			}
+		})
+	}
+}
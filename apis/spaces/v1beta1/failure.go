@@ -0,0 +1,55 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsTerminallyFailed returns true if provisioning this ControlPlane has
+// errored or restoring it from a backup has failed, and no recovery is in
+// progress. A recovery is in progress while the provisioning is being retried,
+// which replaces the ProvisioningError reason of the ControlPlaneProvisioned
+// condition, or while a restore is pending.
+func (mg *ControlPlane) IsTerminallyFailed() bool {
+	if mg.GetCondition(xpv1.TypeReady).Reason == ReasonRestorePending {
+		return false
+	}
+	return isFailed(mg.GetCondition(ConditionTypeControlPlaneProvisioned), ReasonProvisioningError) ||
+		isFailed(mg.GetCondition(ConditionTypeRestored), ReasonRestoreFailed)
+}
+
+func isFailed(c xpv1.Condition, reason xpv1.ConditionReason) bool {
+	return c.Status == corev1.ConditionFalse && c.Reason == reason
+}
+
+// IsReadyWithGrace returns true if this ControlPlane is ready or, while it is
+// being provisioned, if it was created less than the supplied grace period
+// before now. This allows health checks not to flag newly created
+// ControlPlanes as unhealthy before they had a chance to become ready.
+// A ControlPlane that has terminally failed is never considered ready, even
+// within the grace period.
+func (mg *ControlPlane) IsReadyWithGrace(now time.Time, grace time.Duration) bool {
+	if mg.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue {
+		return true
+	}
+	if mg.IsTerminallyFailed() {
+		return false
+	}
+	return now.Sub(mg.GetCreationTimestamp().Time) < grace
+}
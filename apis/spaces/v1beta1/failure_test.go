@@ -0,0 +1,139 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsTerminallyFailed(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		transitions [][]xpv1.Condition
+		want        bool
+	}{
+		"NoConditions": {
+			reason: "A ControlPlane without conditions should not be terminally failed.",
+		},
+		"Provisioning": {
+			reason: "A ControlPlane that is being provisioned should not be terminally failed.",
+			transitions: [][]xpv1.Condition{
+				{ControlPlaneProvisionInProgress(), xpv1.Creating()},
+			},
+		},
+		"ProvisioningThenFailed": {
+			reason: "A ControlPlane whose provisioning has errored after being in progress should be terminally failed.",
+			transitions: [][]xpv1.Condition{
+				{ControlPlaneProvisionInProgress(), xpv1.Creating()},
+				{ControlPlaneProvisioningError(errors.New("boom"))},
+			},
+			want: true,
+		},
+		"FailedThenRetrying": {
+			reason: "A ControlPlane whose provisioning is retried after an error should not be terminally failed.",
+			transitions: [][]xpv1.Condition{
+				{ControlPlaneProvisioningError(errors.New("boom"))},
+				{ControlPlaneProvisionInProgress()},
+			},
+		},
+		"RestorePendingThenFailed": {
+			reason: "A ControlPlane whose restore has failed after being pending should be terminally failed.",
+			transitions: [][]xpv1.Condition{
+				{ControlPlaneProvisioned(), RestorePending()},
+				{RestoreFailed(errors.New("boom")), xpv1.Unavailable()},
+			},
+			want: true,
+		},
+		"RestoreFailedThenPending": {
+			reason: "A ControlPlane with a pending restore after a failed one should not be terminally failed.",
+			transitions: [][]xpv1.Condition{
+				{RestoreFailed(errors.New("boom"))},
+				{RestorePending()},
+			},
+		},
+		"RestoreCompleted": {
+			reason: "A ControlPlane that has been restored should not be terminally failed.",
+			transitions: [][]xpv1.Condition{
+				{ControlPlaneProvisioned(), RestorePending()},
+				{RestoreCompleted(), xpv1.Available()},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{}
+			for _, c := range tc.transitions {
+				mg.SetConditions(c...)
+			}
+			if diff := cmp.Diff(tc.want, mg.IsTerminallyFailed()); diff != "" {
+				t.Errorf("\n%s\nIsTerminallyFailed(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsReadyWithGrace(t *testing.T) {
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	grace := 10 * time.Minute
+
+	cases := map[string]struct {
+		reason string
+		age    time.Duration
+		conds  []xpv1.Condition
+		want   bool
+	}{
+		"ProvisioningWithinGrace": {
+			reason: "A ControlPlane that is being provisioned within the grace period should be considered ready.",
+			age:    grace - time.Second,
+			conds:  []xpv1.Condition{ControlPlaneProvisionInProgress(), xpv1.Creating()},
+			want:   true,
+		},
+		"NoConditionsWithinGrace": {
+			reason: "A ControlPlane without conditions within the grace period should be considered ready.",
+			want:   true,
+		},
+		"ProvisioningAfterGrace": {
+			reason: "A ControlPlane that is still being provisioned after the grace period should not be considered ready.",
+			age:    grace,
+			conds:  []xpv1.Condition{ControlPlaneProvisionInProgress(), xpv1.Creating()},
+		},
+		"ReadyAfterGrace": {
+			reason: "A ready ControlPlane should be considered ready after the grace period.",
+			age:    time.Hour,
+			conds:  []xpv1.Condition{ControlPlaneProvisioned(), xpv1.Available()},
+			want:   true,
+		},
+		"FailedWithinGrace": {
+			reason: "A terminally failed ControlPlane should not be considered ready within the grace period.",
+			age:    time.Minute,
+			conds:  []xpv1.Condition{ControlPlaneProvisioningError(errors.New("boom")), xpv1.Unavailable()},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+			mg.SetConditions(tc.conds...)
+			if diff := cmp.Diff(tc.want, mg.IsReadyWithGrace(created.Add(tc.age), grace)); diff != "" {
+				t.Errorf("\n%s\nIsReadyWithGrace(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
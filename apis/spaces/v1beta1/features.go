@@ -0,0 +1,67 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// FeatureGate is a Spaces feature gate that must be enabled for some of the
+// ControlPlane fields to be supported.
+type FeatureGate string
+
+const (
+	// FeatureGateEnableSharedBackup gates the shared backup and restore
+	// functionality.
+	FeatureGateEnableSharedBackup FeatureGate = "EnableSharedBackup"
+	// FeatureGateEnableKine gates the selection of alternative
+	// KubeControlPlane compositions.
+	FeatureGateEnableKine FeatureGate = "EnableKine"
+)
+
+const (
+	// FieldPathRestore is the path of the restore configuration of a
+	// ControlPlane.
+	FieldPathRestore = "spec.restore"
+	// FieldPathKubeComposition is the path of the annotation selecting the
+	// KubeControlPlane composition of a ControlPlane.
+	FieldPathKubeComposition = "metadata.annotations[" + KubeCompositionAnnotation + "]"
+)
+
+// GatedFields returns the paths of the ControlPlane fields that are gated
+// behind a feature gate, mapped to the gate that must be enabled in the
+// target Space for the field to be supported.
+func GatedFields() map[string]FeatureGate {
+	return map[string]FeatureGate{
+		FieldPathRestore:         FeatureGateEnableSharedBackup,
+		FieldPathKubeComposition: FeatureGateEnableKine,
+	}
+}
+
+// RequiredFeatureGates returns the feature gates that must be enabled in the
+// target Space for this ControlPlane to be supported, in the order of their
+// field paths. The KubeCompositionAnnotation requires its gate only if it
+// selects a composition other than the default one, as returned by
+// DefaultKubeComposition.
+func (mg *ControlPlane) RequiredFeatureGates() []FeatureGate {
+	if mg == nil {
+		return nil
+	}
+	gated := GatedFields()
+	var gates []FeatureGate
+	if mg.KubeComposition() != DefaultKubeComposition() {
+		gates = append(gates, gated[FieldPathKubeComposition])
+	}
+	if mg.Spec.Restore != nil {
+		gates = append(gates, gated[FieldPathRestore])
+	}
+	return gates
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/upbound/up-sdk-go/apis/common"
+)
+
+// TestGatedFieldsInSyncWithMarkers checks that the feature gates referenced by
+// the [[GATE:...]] markers and the gate documentation of the ControlPlane
+// types are exactly the gates reported by GatedFields.
+func TestGatedFieldsInSyncWithMarkers(t *testing.T) {
+	src, err := os.ReadFile("controlplane_types.go")
+	if err != nil {
+		t.Fatalf("cannot read the ControlPlane types: %v", err)
+	}
+	markers := regexp.MustCompile(`\[\[GATE:(\w+)\]\]|gated by the "(\w+)" feature gate`)
+	documented := map[FeatureGate]bool{}
+	for _, m := range markers.FindAllStringSubmatch(string(src), -1) {
+		documented[FeatureGate(m[1]+m[2])] = true
+	}
+	reported := map[FeatureGate]bool{}
+	for _, g := range GatedFields() {
+		reported[g] = true
+	}
+	if diff := cmp.Diff(documented, reported, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("GatedFields(): -documented gates, +reported gates:\n%s", diff)
+	}
+}
+
+func TestRequiredFeatureGates(t *testing.T) {
+	restore := WithRestore(common.TypedLocalObjectReference{Kind: "Backup", Name: "nightly"})
+	withComposition := func(c string) ControlPlaneOption {
+		return func(mg *ControlPlane) {
+			mg.SetAnnotations(map[string]string{KubeCompositionAnnotation: c})
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *ControlPlane
+		want   []FeatureGate
+	}{
+		"Nil": {
+			reason: "A nil ControlPlane should require no feature gates.",
+		},
+		"NoGatedFields": {
+			reason: "A ControlPlane without gated fields should require no feature gates.",
+			mg:     NewControlPlane("ctp", "default", WithVersion("1.15.0-up.1")),
+		},
+		"Restore": {
+			reason: "A ControlPlane restoring from a backup should require the shared backup feature gate.",
+			mg:     NewControlPlane("ctp", "default", restore),
+			want:   []FeatureGate{FeatureGateEnableSharedBackup},
+		},
+		"DefaultKubeComposition": {
+			reason: "A ControlPlane explicitly selecting the default kube composition should require no feature gates.",
+			mg:     NewControlPlane("ctp", "default", withComposition(KubeCompositionK8s)),
+		},
+		"KineKubeComposition": {
+			reason: "A ControlPlane selecting the kine kube composition should require the kine feature gate.",
+			mg:     NewControlPlane("ctp", "default", withComposition(KubeCompositionKine)),
+			want:   []FeatureGate{FeatureGateEnableKine},
+		},
+		"AllGatedFields": {
+			reason: "The feature gates should be returned in the order of their field paths.",
+			mg:     NewControlPlane("ctp", "default", restore, withComposition(KubeCompositionKine)),
+			want:   []FeatureGate{FeatureGateEnableKine, FeatureGateEnableSharedBackup},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.mg.RequiredFeatureGates()
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nRequiredFeatureGates(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	errEmptySSHIdentity     = "SSH identity is empty"
+	errSSHIdentityEncrypted = "SSH identity is protected by a passphrase"
+	errParseSSHIdentity     = "cannot parse SSH identity as a private key"
+	errFmtParseKnownHosts   = "cannot parse known hosts line %d"
+)
+
+// ErrSSHIdentityEncrypted is returned when an SSH identity is a valid private
+// key protected by a passphrase. Such keys cannot be used to access a Git
+// repository as no passphrase can be supplied.
+var ErrSSHIdentityEncrypted = errors.New(errSSHIdentityEncrypted)
+
+// ValidateSSHIdentity checks that the supplied data, typically read from the
+// AuthSecretKeySSHIdentity key of a Git auth secret, is an unencrypted private
+// key. ErrSSHIdentityEncrypted is returned for passphrase protected keys.
+func ValidateSSHIdentity(data []byte) error {
+	if len(data) == 0 {
+		return errors.New(errEmptySSHIdentity)
+	}
+	_, err := ssh.ParseRawPrivateKey(data)
+	var pErr *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &pErr):
+		return ErrSSHIdentityEncrypted
+	case err != nil:
+		return errors.Wrap(err, errParseSSHIdentity)
+	}
+	return nil
+}
+
+// NormalizeKnownHosts parses the supplied known hosts data, typically stored
+// under the AuthSecretKeySSHKnownHosts key of a Git auth secret, and returns
+// it with comments and blank lines dropped, duplicate entries removed and the
+// remaining entries sorted. An error is returned if any line cannot be parsed.
+func NormalizeKnownHosts(data []byte) ([]byte, error) {
+	seen := make(map[string]struct{})
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		marker, hosts, key, _, _, err := ssh.ParseKnownHosts(line)
+		if errors.Is(err, io.EOF) {
+			// blank or comment line
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseKnownHosts, i+1)
+		}
+		entry := strings.Join(hosts, ",") + " " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+		if marker != "" {
+			entry = "@" + marker + " " + entry
+		}
+		seen[entry] = struct{}{}
+	}
+
+	entries := make([]string, 0, len(seen))
+	for e := range seen {
+		entries = append(entries, e)
+	}
+	sort.Strings(entries)
+
+	var b bytes.Buffer
+	for _, e := range entries {
+		b.WriteString(e)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
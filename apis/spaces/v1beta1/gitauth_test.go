@@ -0,0 +1,157 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestValidateSSHIdentity(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(...): %v", err)
+	}
+	plain, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("MarshalPrivateKey(...): %v", err)
+	}
+	encrypted, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("secret"))
+	if err != nil {
+		t.Fatalf("MarshalPrivateKeyWithPassphrase(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		data   []byte
+		want   error
+	}{
+		"Valid": {
+			reason: "An unencrypted OpenSSH private key should be valid.",
+			data:   pem.EncodeToMemory(plain),
+		},
+		"Empty": {
+			reason: "An empty identity should be invalid.",
+			want:   errors.New(errEmptySSHIdentity),
+		},
+		"Encrypted": {
+			reason: "A passphrase protected private key should be rejected with a specific error.",
+			data:   pem.EncodeToMemory(encrypted),
+			want:   ErrSSHIdentityEncrypted,
+		},
+		"NotPEM": {
+			reason: "Data that is not PEM encoded should be invalid.",
+			data:   []byte("not-a-key"),
+			want:   errors.Wrap(errors.New("ssh: no key found"), errParseSSHIdentity),
+		},
+		"PublicKey": {
+			reason: "A public key should be invalid.",
+			data:   []byte("-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n"),
+			want:   errors.Wrap(errors.New(`ssh: unsupported key type "PUBLIC KEY"`), errParseSSHIdentity),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateSSHIdentity(tc.data)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateSSHIdentity(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeKnownHosts(t *testing.T) {
+	keyA := testAuthorizedKey(t)
+	keyB := testAuthorizedKey(t)
+
+	cases := map[string]struct {
+		reason string
+		data   string
+		want   string
+		err    error
+	}{
+		"Empty": {
+			reason: "Empty known hosts should normalize to no entries.",
+		},
+		"CommentsAndBlankLines": {
+			reason: "Comments and blank lines should be dropped.",
+			data:   "# github\n\ngithub.com " + keyA + "\n",
+			want:   "github.com " + keyA + "\n",
+		},
+		"DuplicateHosts": {
+			reason: "Duplicate entries should be collapsed into one.",
+			data:   "github.com " + keyA + "\ngithub.com " + keyA + "\n",
+			want:   "github.com " + keyA + "\n",
+		},
+		"DuplicateHostsWithComments": {
+			reason: "Entries differing only in their trailing comment should be treated as duplicates.",
+			data:   "github.com " + keyA + " first\ngithub.com " + keyA + " second",
+			want:   "github.com " + keyA + "\n",
+		},
+		"SameHostDifferentKeys": {
+			reason: "Entries for the same host with different keys should both be kept.",
+			data:   "github.com " + keyA + "\ngithub.com " + keyB + "\n",
+			want:   strings.Join(sortedStrings("github.com "+keyA, "github.com "+keyB), "\n") + "\n",
+		},
+		"Sorted": {
+			reason: "Entries should be sorted.",
+			data:   "gitlab.com " + keyB + "\n@cert-authority *.example.com " + keyA + "\nbitbucket.org " + keyA + "\n",
+			want:   "@cert-authority *.example.com " + keyA + "\nbitbucket.org " + keyA + "\ngitlab.com " + keyB + "\n",
+		},
+		"InvalidLine": {
+			reason: "An unparseable line should be reported with its line number.",
+			data:   "github.com " + keyA + "\ngitlab.com not-a-key\n",
+			err:    errors.Wrapf(errors.New("ssh: invalid entry in known_hosts data"), errFmtParseKnownHosts, 2),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NormalizeKnownHosts([]byte(tc.data))
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNormalizeKnownHosts(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\nNormalizeKnownHosts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func testAuthorizedKey(t *testing.T) string {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(...): %v", err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("NewPublicKey(...): %v", err)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+}
+
+func sortedStrings(s ...string) []string {
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CanDeleteGroup returns whether the supplied namespace can be deleted as a
+// control plane group that contains the supplied number of ControlPlanes. If
+// it cannot be deleted, a human-readable reason is also returned. A group
+// cannot be deleted if it has the ControlPlaneGroupProtectionKey label set to
+// "true" or if it still contains ControlPlanes.
+func CanDeleteGroup(ns metav1.Object, controlPlaneCount int) (bool, string) {
+	l := ns.GetLabels()
+	if l[ControlPlaneGroupLabelKey] != "true" {
+		return false, fmt.Sprintf("namespace %q is not a control plane group", ns.GetName())
+	}
+	if l[ControlPlaneGroupProtectionKey] == "true" {
+		return false, fmt.Sprintf("group %q is protected from deletion", ns.GetName())
+	}
+	if controlPlaneCount > 0 {
+		return false, fmt.Sprintf("group %q still contains %d control plane(s)", ns.GetName(), controlPlaneCount)
+	}
+	return true, ""
+}
+
+// SameGroup returns true if the supplied ControlPlanes belong to the same
+// control plane group, i.e., they are in the same namespace. It returns false
+// if either of them is nil.
+func SameGroup(a, b *ControlPlane) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.GetNamespace() == b.GetNamespace()
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCanDeleteGroup(t *testing.T) {
+	type want struct {
+		ok     bool
+		reason string
+	}
+	cases := map[string]struct {
+		reason string
+		labels map[string]string
+		count  int
+		want   want
+	}{
+		"NotAGroup": {
+			reason: "A namespace without the group label should not be deleted as a group.",
+			want:   want{reason: `namespace "default" is not a control plane group`},
+		},
+		"Protected": {
+			reason: "A protected group should not be deleted.",
+			labels: map[string]string{ControlPlaneGroupLabelKey: "true", ControlPlaneGroupProtectionKey: "true"},
+			want:   want{reason: `group "default" is protected from deletion`},
+		},
+		"NotEmpty": {
+			reason: "A group that still contains control planes should not be deleted.",
+			labels: map[string]string{ControlPlaneGroupLabelKey: "true"},
+			count:  2,
+			want:   want{reason: `group "default" still contains 2 control plane(s)`},
+		},
+		"Deletable": {
+			reason: "An empty, unprotected group should be deletable.",
+			labels: map[string]string{ControlPlaneGroupLabelKey: "true", ControlPlaneGroupProtectionKey: "false"},
+			want:   want{ok: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: tc.labels}}
+			ok, reason := CanDeleteGroup(ns, tc.count)
+			if diff := cmp.Diff(tc.want, want{ok: ok, reason: reason}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nCanDeleteGroup(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSameGroup(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      *ControlPlane
+		b      *ControlPlane
+		want   bool
+	}{
+		"SameGroup": {
+			reason: "ControlPlanes in the same namespace should be in the same group.",
+			a:      NewControlPlane("ctp1", "team-a"),
+			b:      NewControlPlane("ctp2", "team-a"),
+			want:   true,
+		},
+		"DifferentGroups": {
+			reason: "ControlPlanes in different namespaces should not be in the same group.",
+			a:      NewControlPlane("ctp", "team-a"),
+			b:      NewControlPlane("ctp", "team-b"),
+		},
+		"NilA": {
+			reason: "A nil ControlPlane should not be in the same group as any other.",
+			b:      NewControlPlane("ctp", "team-a"),
+		},
+		"NilB": {
+			reason: "A nil ControlPlane should not be in the same group as any other.",
+			a:      NewControlPlane("ctp", "team-a"),
+		},
+		"BothNil": {
+			reason: "Two nil ControlPlanes should not be in the same group.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, SameGroup(tc.a, tc.b)); diff != "" {
+				t.Errorf("\n%s\nSameGroup(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
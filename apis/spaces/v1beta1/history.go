@@ -0,0 +1,44 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// MaxConditionHistory is the maximum number of condition transitions kept in
+// the ConditionHistory of a ControlPlane's status.
+const MaxConditionHistory = 20
+
+// RecordTransition appends the transition of a condition from one state to
+// another to the ConditionHistory, dropping the oldest transitions so that at
+// most MaxConditionHistory transitions are kept. Only changes of the status or
+// the reason are recorded. A zero from condition denotes a condition that was
+// not set before.
+func (s *ControlPlaneStatus) RecordTransition(from, to xpv1.Condition) {
+	if from.Status == to.Status && from.Reason == to.Reason {
+		return
+	}
+	s.ConditionHistory = append(s.ConditionHistory, ConditionTransition{
+		Type:               to.Type,
+		FromStatus:         from.Status,
+		ToStatus:           to.Status,
+		Reason:             to.Reason,
+		LastTransitionTime: to.LastTransitionTime,
+	})
+	if n := len(s.ConditionHistory); n > MaxConditionHistory {
+		s.ConditionHistory = s.ConditionHistory[n-MaxConditionHistory:]
+	}
+}
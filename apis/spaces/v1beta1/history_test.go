@@ -0,0 +1,119 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordTransition(t *testing.T) {
+	now := metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	healthy := Healthy()
+	healthy.LastTransitionTime = now
+	unhealthy := Unhealthy()
+	unhealthy.LastTransitionTime = now
+
+	// transitions returns n Healthy transitions, the i-th having
+	// its time set to i seconds after now.
+	transitions := func(n int) []ConditionTransition {
+		h := make([]ConditionTransition, n)
+		for i := range h {
+			h[i] = ConditionTransition{
+				Type:               ConditionTypeHealthy,
+				FromStatus:         corev1.ConditionTrue,
+				ToStatus:           corev1.ConditionFalse,
+				Reason:             ReasonUnhealthy,
+				LastTransitionTime: metav1.NewTime(now.Add(time.Duration(i) * time.Second)),
+			}
+		}
+		return h
+	}
+	recorded := ConditionTransition{
+		Type:               ConditionTypeHealthy,
+		FromStatus:         corev1.ConditionTrue,
+		ToStatus:           corev1.ConditionFalse,
+		Reason:             ReasonUnhealthy,
+		LastTransitionTime: now,
+	}
+
+	cases := map[string]struct {
+		reason  string
+		history []ConditionTransition
+		from    xpv1.Condition
+		to      xpv1.Condition
+		want    []ConditionTransition
+	}{
+		"FirstCondition": {
+			reason: "A condition that was not set before should be recorded as a transition from an empty status.",
+			to:     healthy,
+			want: []ConditionTransition{{
+				Type:               ConditionTypeHealthy,
+				ToStatus:           corev1.ConditionTrue,
+				Reason:             ReasonHealthy,
+				LastTransitionTime: now,
+			}},
+		},
+		"Transition": {
+			reason:  "A status change should be appended to the history.",
+			history: transitions(1),
+			from:    healthy,
+			to:      unhealthy,
+			want:    append(transitions(1), recorded),
+		},
+		"NoChange": {
+			reason:  "An unchanged condition should not be recorded.",
+			history: transitions(1),
+			from:    healthy,
+			to:      healthy,
+			want:    transitions(1),
+		},
+		"BelowCap": {
+			reason:  "A transition should be appended while the history has fewer than the maximum number of entries.",
+			history: transitions(MaxConditionHistory - 1),
+			from:    healthy,
+			to:      unhealthy,
+			want:    append(transitions(MaxConditionHistory-1), recorded),
+		},
+		"AtCap": {
+			reason:  "The oldest transition should be dropped when the history is full.",
+			history: transitions(MaxConditionHistory),
+			from:    healthy,
+			to:      unhealthy,
+			want:    append(transitions(MaxConditionHistory)[1:], recorded),
+		},
+		"OverCap": {
+			reason:  "A history exceeding the maximum number of entries should be trimmed to the most recent entries.",
+			history: transitions(MaxConditionHistory + 5),
+			from:    healthy,
+			to:      unhealthy,
+			want:    append(transitions(MaxConditionHistory + 5)[6:], recorded),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &ControlPlaneStatus{ConditionHistory: tc.history}
+			s.RecordTransition(tc.from, tc.to)
+			if diff := cmp.Diff(tc.want, s.ConditionHistory); diff != "" {
+				t.Errorf("\n%s\nRecordTransition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
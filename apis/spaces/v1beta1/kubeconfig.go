@@ -0,0 +1,159 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNilSecret           = "connection secret cannot be nil"
+	errFmtMissingKey       = "connection secret %s/%s has no %q key"
+	errFmtParseKubeconfig  = "cannot parse the %q key of connection secret %s/%s"
+	errNewClient           = "cannot create a client for the control plane"
+	errFmtInvalidContext   = "current context %q of connection secret %s/%s is invalid"
+	errWriteKubeconfig     = "cannot write the merged kubeconfig"
+	errFmtDuplicateContext = "control planes %s and %s have the same context name %q"
+)
+
+// InClusterRESTConfig returns a REST config for the ControlPlane whose
+// connection secret is given, using the kubeconfig stored under the
+// ResourceCredentialsSecretInClusterKubeconfigKey key. The returned config is
+// only usable by workloads running in the cluster hosting the ControlPlane.
+func InClusterRESTConfig(secret *corev1.Secret) (*rest.Config, error) {
+	return restConfigFromSecret(secret, ResourceCredentialsSecretInClusterKubeconfigKey)
+}
+
+// ClientFromConnectionSecret returns a controller-runtime client for the
+// ControlPlane whose connection secret is given, using the supplied scheme
+// and the kubeconfig stored under the supplied key of the secret. The key is
+// either ResourceCredentialsSecretInClusterKubeconfigKey, for workloads
+// running in the cluster hosting the ControlPlane, or
+// xpv1.ResourceCredentialsSecretKubeconfigKey.
+func ClientFromConnectionSecret(secret *corev1.Secret, scheme *runtime.Scheme, key string) (client.Client, error) {
+	cfg, err := restConfigFromSecret(secret, key)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	return c, errors.Wrap(err, errNewClient)
+}
+
+func restConfigFromSecret(secret *corev1.Secret, key string) (*rest.Config, error) {
+	if secret == nil {
+		return nil, errors.New(errNilSecret)
+	}
+	kc, ok := secret.Data[key]
+	if !ok || len(kc) == 0 {
+		return nil, errors.Errorf(errFmtMissingKey, secret.GetNamespace(), secret.GetName(), key)
+	}
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kc)
+	return cfg, errors.Wrapf(err, errFmtParseKubeconfig, key, secret.GetNamespace(), secret.GetName())
+}
+
+// KubeContextName returns the name of the kubeconfig context for this
+// ControlPlane, in the form of "<group>_<name>", so that the contexts of
+// ControlPlanes in different groups do not collide in a combined kubeconfig.
+// The separator '_' cannot appear in a group or a ControlPlane name, so the
+// context names of different ControlPlanes are distinct. Any character other
+// than an alphanumeric character, '-' or '.' in the group or the name is
+// escaped as '%' followed by the hexadecimal value of each of its bytes, so
+// that the name is safe to use in shells and file paths and the escaping
+// does not introduce collisions either.
+func (mg *ControlPlane) KubeContextName() string {
+	return sanitizeContextName(mg.GetNamespace()) + "_" + sanitizeContextName(mg.GetName())
+}
+
+func sanitizeContextName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '.':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// MergeKubeconfigs returns a single kubeconfig with a context per supplied
+// ControlPlane, named after its KubeContextName, so that a user can log in to
+// all of them at once. The kubeconfig of each ControlPlane is read from the
+// xpv1.ResourceCredentialsSecretKubeconfigKey key of its connection secret,
+// looked up in the supplied secrets by the string form of its
+// ResolvedConnectionSecretRef, i.e., "<namespace>/<name>". ControlPlanes whose
+// connection secrets or kubeconfigs are not available are skipped, and their
+// "<namespace>/<name>" are returned in the order they are supplied. Nil
+// ControlPlanes are ignored. An error is returned if two ControlPlanes have
+// the same context name, e.g., if a ControlPlane is supplied twice, rather
+// than overwriting the context of one with the other. The current context of
+// the returned kubeconfig is not set.
+func MergeKubeconfigs(ctps []*ControlPlane, secrets map[string]*corev1.Secret) ([]byte, []string, error) {
+	merged := clientcmdapi.NewConfig()
+	owners := make(map[string]string, len(ctps))
+	var skipped []string
+	for _, mg := range ctps {
+		if mg == nil {
+			continue
+		}
+		id := mg.GetNamespace() + "/" + mg.GetName()
+		name := mg.KubeContextName()
+		if other, ok := owners[name]; ok {
+			return nil, nil, errors.Errorf(errFmtDuplicateContext, other, id, name)
+		}
+		owners[name] = id
+		secret := secrets[connectionSecretKey(mg)]
+		if secret == nil || len(secret.Data[xpv1.ResourceCredentialsSecretKubeconfigKey]) == 0 {
+			skipped = append(skipped, id)
+			continue
+		}
+		kc, err := clientcmd.Load(secret.Data[xpv1.ResourceCredentialsSecretKubeconfigKey])
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtParseKubeconfig, xpv1.ResourceCredentialsSecretKubeconfigKey, secret.GetNamespace(), secret.GetName())
+		}
+		ctx := kc.Contexts[kc.CurrentContext]
+		if ctx == nil || kc.Clusters[ctx.Cluster] == nil || kc.AuthInfos[ctx.AuthInfo] == nil {
+			return nil, nil, errors.Errorf(errFmtInvalidContext, kc.CurrentContext, secret.GetNamespace(), secret.GetName())
+		}
+		merged.Clusters[name] = kc.Clusters[ctx.Cluster]
+		merged.AuthInfos[name] = kc.AuthInfos[ctx.AuthInfo]
+		merged.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name, Namespace: ctx.Namespace}
+	}
+	b, err := clientcmd.Write(*merged)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errWriteKubeconfig)
+	}
+	return b, skipped, nil
+}
+
+func connectionSecretKey(mg *ControlPlane) string {
+	ref, ok := mg.ResolvedConnectionSecretRef()
+	if !ok {
+		return ""
+	}
+	return ref.String()
+}
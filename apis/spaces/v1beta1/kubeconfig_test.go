@@ -0,0 +1,326 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: ctp
+  cluster:
+    server: https://ctp.default.svc:6443
+contexts:
+- name: ctp
+  context:
+    cluster: ctp
+    user: ctp
+current-context: ctp
+users:
+- name: ctp
+  user:
+    token: secret-token
+`
+
+func TestInClusterRESTConfig(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "kubeconfig-ctp", Namespace: "default"}
+
+	type want struct {
+		host string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		secret *corev1.Secret
+		want   want
+	}{
+		"NilSecret": {
+			reason: "A nil secret should return an error.",
+			want: want{
+				err: errors.New(errNilSecret),
+			},
+		},
+		"MissingKey": {
+			reason: "A secret without the in-cluster kubeconfig key should return an error.",
+			secret: &corev1.Secret{
+				ObjectMeta: meta,
+				Data:       map[string][]byte{"kubeconfig": []byte(testKubeconfig)},
+			},
+			want: want{
+				err: errors.Errorf(errFmtMissingKey, "default", "kubeconfig-ctp", ResourceCredentialsSecretInClusterKubeconfigKey),
+			},
+		},
+		"Malformed": {
+			reason: "A secret with a malformed kubeconfig should return an error.",
+			secret: &corev1.Secret{
+				ObjectMeta: meta,
+				Data:       map[string][]byte{ResourceCredentialsSecretInClusterKubeconfigKey: []byte("{")},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("yaml: line 1: did not find expected node content"), errFmtParseKubeconfig, ResourceCredentialsSecretInClusterKubeconfigKey, "default", "kubeconfig-ctp"),
+			},
+		},
+		"Success": {
+			reason: "A secret with a valid in-cluster kubeconfig should return a REST config.",
+			secret: &corev1.Secret{
+				ObjectMeta: meta,
+				Data:       map[string][]byte{ResourceCredentialsSecretInClusterKubeconfigKey: []byte(testKubeconfig)},
+			},
+			want: want{
+				host: "https://ctp.default.svc:6443",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := InClusterRESTConfig(tc.secret)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nInClusterRESTConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.host, cfg.Host); diff != "" {
+				t.Errorf("\n%s\nInClusterRESTConfig(...): -want host, +got host:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClientFromConnectionSecret(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "kubeconfig-ctp", Namespace: "default"}
+	scheme := runtime.NewScheme()
+
+	cases := map[string]struct {
+		reason string
+		secret *corev1.Secret
+		key    string
+		want   error
+	}{
+		"NilSecret": {
+			reason: "A nil secret should return an error.",
+			key:    xpv1.ResourceCredentialsSecretKubeconfigKey,
+			want:   errors.New(errNilSecret),
+		},
+		"MissingKey": {
+			reason: "A secret without the requested kubeconfig key should return an error.",
+			secret: &corev1.Secret{
+				ObjectMeta: meta,
+				Data:       map[string][]byte{xpv1.ResourceCredentialsSecretKubeconfigKey: []byte(testKubeconfig)},
+			},
+			key:  ResourceCredentialsSecretInClusterKubeconfigKey,
+			want: errors.Errorf(errFmtMissingKey, "default", "kubeconfig-ctp", ResourceCredentialsSecretInClusterKubeconfigKey),
+		},
+		"External": {
+			reason: "A client should be created from the external kubeconfig.",
+			secret: &corev1.Secret{
+				ObjectMeta: meta,
+				Data:       map[string][]byte{xpv1.ResourceCredentialsSecretKubeconfigKey: []byte(testKubeconfig)},
+			},
+			key: xpv1.ResourceCredentialsSecretKubeconfigKey,
+		},
+		"InCluster": {
+			reason: "A client should be created from the in-cluster kubeconfig.",
+			secret: &corev1.Secret{
+				ObjectMeta: meta,
+				Data:       map[string][]byte{ResourceCredentialsSecretInClusterKubeconfigKey: []byte(testKubeconfig)},
+			},
+			key: ResourceCredentialsSecretInClusterKubeconfigKey,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := ClientFromConnectionSecret(tc.secret, scheme, tc.key)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nClientFromConnectionSecret(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if c.Scheme() != scheme {
+				t.Errorf("\n%s\nClientFromConnectionSecret(...): client does not use the supplied scheme", tc.reason)
+			}
+		})
+	}
+}
+
+func TestKubeContextName(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		namespace string
+		name      string
+		want      string
+	}{
+		"Valid": {
+			reason:    "The context name should be the group and the name of the ControlPlane.",
+			namespace: "default",
+			name:      "ctp",
+			want:      "default_ctp",
+		},
+		"Dashes": {
+			reason:    "Dashes in the group and the name should be kept.",
+			namespace: "team-a",
+			name:      "prod-1",
+			want:      "team-a_prod-1",
+		},
+		"Sanitized": {
+			reason:    "Characters that are unsafe in a context name, including the separator, should be escaped.",
+			namespace: "team a",
+			name:      "ctp/1:prod_%",
+			want:      "team%20a_ctp%2F1%3Aprod%5F%25",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{ObjectMeta: metav1.ObjectMeta{Namespace: tc.namespace, Name: tc.name}}
+			if diff := cmp.Diff(tc.want, mg.KubeContextName()); diff != "" {
+				t.Errorf("\n%s\nKubeContextName(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubeContextNameCollisions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      metav1.ObjectMeta
+		b      metav1.ObjectMeta
+	}{
+		"DashInGroup": {
+			reason: "A dash moved between the group and the name should not produce the same context name.",
+			a:      metav1.ObjectMeta{Namespace: "team-a", Name: "prod"},
+			b:      metav1.ObjectMeta{Namespace: "team", Name: "a-prod"},
+		},
+		"Sanitized": {
+			reason: "An escaped character should not produce the same context name as a dash.",
+			a:      metav1.ObjectMeta{Namespace: "team a", Name: "prod"},
+			b:      metav1.ObjectMeta{Namespace: "team-a", Name: "prod"},
+		},
+		"Separator": {
+			reason: "A separator in the group should not produce the same context name as a separator between the group and the name.",
+			a:      metav1.ObjectMeta{Namespace: "team_a", Name: "prod"},
+			b:      metav1.ObjectMeta{Namespace: "team", Name: "a_prod"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, b := (&ControlPlane{ObjectMeta: tc.a}).KubeContextName(), (&ControlPlane{ObjectMeta: tc.b}).KubeContextName()
+			if a == b {
+				t.Errorf("\n%s\nKubeContextName(): want distinct context names, got %q for both", tc.reason, a)
+			}
+		})
+	}
+}
+
+func TestMergeKubeconfigs(t *testing.T) {
+	ctp := func(ns, name string) *ControlPlane {
+		return &ControlPlane{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+	secret := func(ns, name, kubeconfig string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Data:       map[string][]byte{xpv1.ResourceCredentialsSecretKubeconfigKey: []byte(kubeconfig)},
+		}
+	}
+	other := strings.ReplaceAll(strings.ReplaceAll(testKubeconfig, "ctp.default.svc", "ctp.team.svc"), "secret-token", "other-token")
+
+	type want struct {
+		servers map[string]string
+		tokens  map[string]string
+		skipped []string
+		err     error
+	}
+	cases := map[string]struct {
+		reason  string
+		ctps    []*ControlPlane
+		secrets map[string]*corev1.Secret
+		want    want
+	}{
+		"Empty": {
+			reason: "An empty kubeconfig should be returned if there are no ControlPlanes.",
+			want:   want{servers: map[string]string{}, tokens: map[string]string{}},
+		},
+		"Merged": {
+			reason: "A context should be added per ControlPlane, ControlPlanes without available secrets should be skipped and nil ControlPlanes ignored.",
+			ctps:   []*ControlPlane{ctp("default", "a"), nil, ctp("default", "b"), ctp("team", "c"), ctp("team", "d")},
+			secrets: map[string]*corev1.Secret{
+				"default/kubeconfig-a": secret("default", "kubeconfig-a", testKubeconfig),
+				"team/kubeconfig-c":    secret("team", "kubeconfig-c", other),
+				"team/kubeconfig-d":    {ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "kubeconfig-d"}},
+			},
+			want: want{
+				servers: map[string]string{"default_a": "https://ctp.default.svc:6443", "team_c": "https://ctp.team.svc:6443"},
+				tokens:  map[string]string{"default_a": "secret-token", "team_c": "other-token"},
+				skipped: []string{"default/b", "team/d"},
+			},
+		},
+		"DuplicateContext": {
+			reason:  "An error should be returned if two ControlPlanes have the same context name.",
+			ctps:    []*ControlPlane{ctp("default", "a"), ctp("default", "a")},
+			secrets: map[string]*corev1.Secret{"default/kubeconfig-a": secret("default", "kubeconfig-a", testKubeconfig)},
+			want: want{
+				err: errors.Errorf(errFmtDuplicateContext, "default/a", "default/a", "default_a"),
+			},
+		},
+		"InvalidKubeconfig": {
+			reason:  "An error should be returned if a kubeconfig cannot be parsed.",
+			ctps:    []*ControlPlane{ctp("default", "a")},
+			secrets: map[string]*corev1.Secret{"default/kubeconfig-a": secret("default", "kubeconfig-a", "{")},
+			want: want{
+				err: errors.Wrapf(errors.New("yaml: line 1: did not find expected node content"), errFmtParseKubeconfig, xpv1.ResourceCredentialsSecretKubeconfigKey, "default", "kubeconfig-a"),
+			},
+		},
+		"InvalidContext": {
+			reason:  "An error should be returned if the current context of a kubeconfig does not exist.",
+			ctps:    []*ControlPlane{ctp("default", "a")},
+			secrets: map[string]*corev1.Secret{"default/kubeconfig-a": secret("default", "kubeconfig-a", strings.ReplaceAll(testKubeconfig, "current-context: ctp", "current-context: missing"))},
+			want: want{
+				err: errors.Errorf(errFmtInvalidContext, "missing", "default", "kubeconfig-a"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, skipped, err := MergeKubeconfigs(tc.ctps, tc.secrets)
+			got := want{skipped: skipped, err: err}
+			if err == nil {
+				kc, err := clientcmd.Load(b)
+				if err != nil {
+					t.Fatalf("\n%s\nMergeKubeconfigs(...): cannot load the merged kubeconfig: %v", tc.reason, err)
+				}
+				got.servers, got.tokens = map[string]string{}, map[string]string{}
+				for n, c := range kc.Contexts {
+					got.servers[n] = kc.Clusters[c.Cluster].Server
+					got.tokens[n] = kc.AuthInfos[c.AuthInfo].Token
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMergeKubeconfigs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	errFmtChannelNotAllowed    = "upgrade channel %q is not allowed: must be one of %s"
+	errVersionPinRequired      = "crossplane version must be pinned"
+	errDeprecatedConnectionRef = "writeConnectionSecretToRef is deprecated and must not be set"
+)
+
+// A PolicyCheck checks a ControlPlane against an organizational policy and
+// returns an error describing the violation, if any.
+// +kubebuilder:object:generate=false
+type PolicyCheck func(mg *ControlPlane) error
+
+// CheckPolicies runs the supplied checks against this ControlPlane and returns
+// the errors of all the violated ones.
+func (mg *ControlPlane) CheckPolicies(checks ...PolicyCheck) []error {
+	var errs []error
+	for _, check := range checks {
+		if err := check(mg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// AllowedChannels returns a PolicyCheck that requires the effective upgrade
+// channel of a ControlPlane to be one of the supplied channels.
+func AllowedChannels(channels ...CrossplaneUpgradeChannel) PolicyCheck {
+	return func(mg *ControlPlane) error {
+		c := mg.Spec.Crossplane.EffectiveChannel()
+		names := make([]string, len(channels))
+		for i, allowed := range channels {
+			if c == allowed {
+				return nil
+			}
+			names[i] = string(allowed)
+		}
+		return errors.Errorf(errFmtChannelNotAllowed, c, strings.Join(names, ", "))
+	}
+}
+
+// ValidateChannelPolicy checks that the effective upgrade channel of this
+// ControlPlane, i.e., the Stable channel unless a channel is specified, is one
+// of the allowed channels, e.g., to forbid the Rapid channel in production
+// groups. It is equivalent to running the AllowedChannels check.
+func (mg *ControlPlane) ValidateChannelPolicy(allowed []CrossplaneUpgradeChannel) error {
+	return AllowedChannels(allowed...)(mg)
+}
+
+// RequireVersionPin returns a PolicyCheck that requires a ControlPlane to
+// specify a Crossplane version.
+func RequireVersionPin() PolicyCheck {
+	return func(mg *ControlPlane) error {
+		if ptr.Deref(mg.Spec.Crossplane.Version, "") == "" {
+			return errors.New(errVersionPinRequired)
+		}
+		return nil
+	}
+}
+
+// ForbidDeprecatedFields returns a PolicyCheck that requires a ControlPlane
+// not to set any deprecated fields.
+func ForbidDeprecatedFields() PolicyCheck {
+	return func(mg *ControlPlane) error {
+		if mg.Spec.WriteConnectionSecretToReference != nil {
+			return errors.New(errDeprecatedConnectionRef)
+		}
+		return nil
+	}
+}
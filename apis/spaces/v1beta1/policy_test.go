@@ -0,0 +1,132 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+func TestCheckPolicies(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		spec   ControlPlaneSpec
+		checks []PolicyCheck
+		want   []error
+	}{
+		"NoChecks": {
+			reason: "A ControlPlane should not violate any policies if there are no checks.",
+		},
+		"Compliant": {
+			reason: "A ControlPlane satisfying all the checks should not violate any policies.",
+			spec: ControlPlaneSpec{Crossplane: CrossplaneSpec{
+				Version:         ptr.To("1.15.0-up.1"),
+				AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradePatch)},
+			}},
+			checks: []PolicyCheck{
+				AllowedChannels(CrossplaneUpgradeNone, CrossplaneUpgradePatch),
+				RequireVersionPin(),
+				ForbidDeprecatedFields(),
+			},
+		},
+		"DefaultChannel": {
+			reason: "A ControlPlane without a channel should be checked against the default Stable channel.",
+			checks: []PolicyCheck{AllowedChannels(CrossplaneUpgradeNone, CrossplaneUpgradePatch)},
+			want:   []error{errors.Errorf(errFmtChannelNotAllowed, CrossplaneUpgradeStable, "None, Patch")},
+		},
+		"MultipleViolations": {
+			reason: "All violated policies should be reported in the order of the checks.",
+			spec: ControlPlaneSpec{
+				WriteConnectionSecretToReference: &SecretReference{Name: "kubeconfig-ctp"},
+				Crossplane: CrossplaneSpec{
+					AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeRapid)},
+				},
+			},
+			checks: []PolicyCheck{
+				AllowedChannels(CrossplaneUpgradeStable),
+				RequireVersionPin(),
+				ForbidDeprecatedFields(),
+				func(_ *ControlPlane) error { return errBoom },
+			},
+			want: []error{
+				errors.Errorf(errFmtChannelNotAllowed, CrossplaneUpgradeRapid, "Stable"),
+				errors.New(errVersionPinRequired),
+				errors.New(errDeprecatedConnectionRef),
+				errBoom,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{Spec: tc.spec}
+			got := mg.CheckPolicies(tc.checks...)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckPolicies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateChannelPolicy(t *testing.T) {
+	production := []CrossplaneUpgradeChannel{CrossplaneUpgradeNone, CrossplaneUpgradePatch, CrossplaneUpgradeStable}
+
+	cases := map[string]struct {
+		reason  string
+		spec    CrossplaneSpec
+		allowed []CrossplaneUpgradeChannel
+		want    error
+	}{
+		"Allowed": {
+			reason:  "An allowed channel should be valid.",
+			spec:    CrossplaneSpec{AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradePatch)}},
+			allowed: production,
+		},
+		"NotAllowed": {
+			reason:  "A channel that is not allowed should be rejected.",
+			spec:    CrossplaneSpec{AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeRapid)}},
+			allowed: production,
+			want:    errors.Errorf(errFmtChannelNotAllowed, CrossplaneUpgradeRapid, "None, Patch, Stable"),
+		},
+		"DefaultChannelAllowed": {
+			reason:  "A ControlPlane without an auto-upgrade configuration should be checked against the default Stable channel.",
+			allowed: production,
+		},
+		"DefaultChannelNotAllowed": {
+			reason:  "A ControlPlane without a channel should be rejected if the default Stable channel is not allowed.",
+			spec:    CrossplaneSpec{AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{}},
+			allowed: []CrossplaneUpgradeChannel{CrossplaneUpgradeNone},
+			want:    errors.Errorf(errFmtChannelNotAllowed, CrossplaneUpgradeStable, "None"),
+		},
+		"NoneAllowed": {
+			reason: "Any channel should be rejected if no channels are allowed.",
+			want:   errors.Errorf(errFmtChannelNotAllowed, CrossplaneUpgradeStable, ""),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{Spec: ControlPlaneSpec{Crossplane: tc.spec}}
+			err := mg.ValidateChannelPolicy(tc.allowed)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateChannelPolicy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
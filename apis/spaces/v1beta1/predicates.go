@@ -0,0 +1,60 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+type conditioned interface {
+	GetCondition(ct xpv1.ConditionType) xpv1.Condition
+}
+
+// ReadinessChangedPredicate returns a predicate that filters out the update
+// events of ControlPlanes whose Ready condition status has not changed. A
+// missing Ready condition is considered to have the Unknown status. Create,
+// delete and generic events are not filtered.
+func ReadinessChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj, ok := e.ObjectOld.(conditioned)
+			if !ok {
+				return true
+			}
+			newObj, ok := e.ObjectNew.(conditioned)
+			if !ok {
+				return true
+			}
+			return oldObj.GetCondition(xpv1.TypeReady).Status != newObj.GetCondition(xpv1.TypeReady).Status
+		},
+	}
+}
+
+// StatusNeedsUpdate returns true if the status of the new ControlPlane
+// semantically differs from the status of the old one, i.e., if their
+// messages, control plane IDs or conditions differ. The order and the last
+// transition times of the conditions are ignored, so that controllers can
+// avoid status writes that would only cause watch churn.
+func StatusNeedsUpdate(oldObj, newObj *ControlPlane) bool {
+	if oldObj == nil || newObj == nil {
+		return oldObj != newObj
+	}
+	o, n := oldObj.Status, newObj.Status
+	return o.Message != n.Message ||
+		o.ControlPlaneID != n.ControlPlaneID ||
+		!o.ConditionedStatus.Equal(&n.ConditionedStatus)
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func controlPlaneWithConditions(c ...xpv1.Condition) *ControlPlane {
+	ctp := &ControlPlane{}
+	ctp.SetConditions(c...)
+	return ctp
+}
+
+func TestReadinessChangedPredicate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		old    *ControlPlane
+		new    *ControlPlane
+		want   bool
+	}{
+		"Unchanged": {
+			reason: "An update that does not change the Ready condition status should be filtered out.",
+			old:    controlPlaneWithConditions(xpv1.Available()),
+			new:    controlPlaneWithConditions(xpv1.Available(), Healthy()),
+		},
+		"ReasonChanged": {
+			reason: "An update that only changes the Ready condition reason should be filtered out.",
+			old:    controlPlaneWithConditions(xpv1.Creating()),
+			new:    controlPlaneWithConditions(RestorePending()),
+		},
+		"BecameReady": {
+			reason: "An update that changes the Ready condition status should pass.",
+			old:    controlPlaneWithConditions(xpv1.Creating()),
+			new:    controlPlaneWithConditions(xpv1.Available()),
+			want:   true,
+		},
+		"ConditionAdded": {
+			reason: "An update that adds the Ready condition should pass.",
+			old:    controlPlaneWithConditions(),
+			new:    controlPlaneWithConditions(xpv1.Unavailable()),
+			want:   true,
+		},
+		"ConditionRemoved": {
+			reason: "An update that removes the Ready condition should pass.",
+			old:    controlPlaneWithConditions(xpv1.Available()),
+			new:    controlPlaneWithConditions(),
+			want:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ReadinessChangedPredicate().Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStatusNeedsUpdate(t *testing.T) {
+	earlier := xpv1.Available()
+	earlier.LastTransitionTime = metav1.NewTime(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	later := xpv1.Available()
+	later.LastTransitionTime = metav1.NewTime(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC))
+
+	withStatus := func(msg, id string, c ...xpv1.Condition) *ControlPlane {
+		ctp := controlPlaneWithConditions(c...)
+		ctp.Status.Message = msg
+		ctp.Status.ControlPlaneID = id
+		return ctp
+	}
+
+	cases := map[string]struct {
+		reason string
+		old    *ControlPlane
+		new    *ControlPlane
+		want   bool
+	}{
+		"BothNil": {
+			reason: "Two nil ControlPlanes should not need a status update.",
+		},
+		"OldNil": {
+			reason: "A status update should be needed if there is no old ControlPlane.",
+			new:    withStatus("", ""),
+			want:   true,
+		},
+		"Unchanged": {
+			reason: "Identical statuses should not need an update.",
+			old:    withStatus("ready", "id", earlier, Healthy()),
+			new:    withStatus("ready", "id", earlier, Healthy()),
+		},
+		"TimestampOnly": {
+			reason: "Statuses that only differ in condition timestamps should not need an update.",
+			old:    withStatus("ready", "id", earlier),
+			new:    withStatus("ready", "id", later),
+		},
+		"ConditionOrder": {
+			reason: "Statuses that only differ in the order of conditions should not need an update.",
+			old:    withStatus("ready", "id", earlier, Healthy()),
+			new:    withStatus("ready", "id", Healthy(), later),
+		},
+		"MessageChanged": {
+			reason: "A changed message should need an update.",
+			old:    withStatus("creating", "id", earlier),
+			new:    withStatus("ready", "id", earlier),
+			want:   true,
+		},
+		"IDChanged": {
+			reason: "A changed control plane ID should need an update.",
+			old:    withStatus("ready", "", earlier),
+			new:    withStatus("ready", "id", earlier),
+			want:   true,
+		},
+		"ConditionChanged": {
+			reason: "A changed condition should need an update.",
+			old:    withStatus("ready", "id", earlier, Healthy()),
+			new:    withStatus("ready", "id", earlier, Unhealthy()),
+			want:   true,
+		},
+		"ConditionAdded": {
+			reason: "An added condition should need an update.",
+			old:    withStatus("ready", "id", earlier),
+			new:    withStatus("ready", "id", earlier, Healthy()),
+			want:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, StatusNeedsUpdate(tc.old, tc.new)); diff != "" {
+				t.Errorf("\n%s\nStatusNeedsUpdate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"reflect"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// verbRestore is the verb required on a Backup or BackupSchedule to
+	// restore a ControlPlane from it.
+	verbRestore = "restore"
+)
+
+// RequiredRBAC returns the deduplicated set of RBAC rules granting access to
+// the resources referenced by this ControlPlane, i.e., its connection secret
+// and, if a restore is configured, the Backup or BackupSchedule it is
+// restored from. The rules do not carry namespaces, and are meant to be bound
+// in the namespaces of the referenced resources.
+func (mg *ControlPlane) RequiredRBAC() []rbacv1.PolicyRule {
+	var rules []rbacv1.PolicyRule
+	if secret, ok := mg.ResolvedConnectionSecretRef(); ok {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{secret.Name},
+			Verbs:         []string{"get"},
+		})
+	}
+	if r := mg.Spec.Restore; r != nil {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{ptr.Deref(r.Source.APIGroup, Group)},
+			Resources:     []string{strings.ToLower(r.Source.Kind) + "s"},
+			ResourceNames: []string{r.Source.Name},
+			Verbs:         []string{"get", verbRestore},
+		})
+	}
+	return dedupRules(rules)
+}
+
+// dedupRules returns the supplied rules without duplicates, preserving the
+// order of their first occurrences.
+func dedupRules(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	deduped := make([]rbacv1.PolicyRule, 0, len(rules))
+	for _, r := range rules {
+		dup := false
+		for _, d := range deduped {
+			if reflect.DeepEqual(r, d) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			deduped = append(deduped, r)
+		}
+	}
+	return deduped
+}
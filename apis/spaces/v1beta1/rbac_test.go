@@ -0,0 +1,100 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/upbound/up-sdk-go/apis/common"
+)
+
+func TestRequiredRBAC(t *testing.T) {
+	defaultSecret := rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"secrets"},
+		ResourceNames: []string{"kubeconfig-ctp"},
+		Verbs:         []string{"get"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   ControlPlaneSpec
+		want   []rbacv1.PolicyRule
+	}{
+		"DefaultConnectionSecret": {
+			reason: "Access to the default connection secret should be required if no secret is referenced.",
+			want:   []rbacv1.PolicyRule{defaultSecret},
+		},
+		"ConnectionSecret": {
+			reason: "Access to the referenced connection secret should be required.",
+			spec: ControlPlaneSpec{
+				WriteConnectionSecretToReference: &SecretReference{Name: "ctp-conn", Namespace: "default"},
+			},
+			want: []rbacv1.PolicyRule{{
+				APIGroups:     []string{""},
+				Resources:     []string{"secrets"},
+				ResourceNames: []string{"ctp-conn"},
+				Verbs:         []string{"get"},
+			}},
+		},
+		"RestoreFromBackup": {
+			reason: "The restore permission on the source Backup should be required if a restore is configured.",
+			spec: ControlPlaneSpec{
+				Restore: &Restore{Source: common.TypedLocalObjectReference{Kind: "Backup", Name: "nightly"}},
+			},
+			want: []rbacv1.PolicyRule{defaultSecret, {
+				APIGroups:     []string{Group},
+				Resources:     []string{"backups"},
+				ResourceNames: []string{"nightly"},
+				Verbs:         []string{"get", verbRestore},
+			}},
+		},
+		"RestoreFromBackupSchedule": {
+			reason: "The restore permission on the source BackupSchedule should be required if a restore is configured.",
+			spec: ControlPlaneSpec{
+				Restore: &Restore{Source: common.TypedLocalObjectReference{APIGroup: ptr.To(Group), Kind: "BackupSchedule", Name: "daily"}},
+			},
+			want: []rbacv1.PolicyRule{defaultSecret, {
+				APIGroups:     []string{Group},
+				Resources:     []string{"backupschedules"},
+				ResourceNames: []string{"daily"},
+				Verbs:         []string{"get", verbRestore},
+			}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{ObjectMeta: metav1.ObjectMeta{Name: "ctp", Namespace: "default"}, Spec: tc.spec}
+			got := mg.RequiredRBAC()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRequiredRBAC(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDedupRules(t *testing.T) {
+	r1 := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}
+	r2 := rbacv1.PolicyRule{APIGroups: []string{Group}, Resources: []string{"backups"}, Verbs: []string{"get"}}
+	want := []rbacv1.PolicyRule{r1, r2}
+	if diff := cmp.Diff(want, dedupRules([]rbacv1.PolicyRule{r1, r2, r1, r2})); diff != "" {
+		t.Errorf("\ndedupRules(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// restoreSourceKindBackup is the kind of a Backup restore source.
+	restoreSourceKindBackup = "Backup"
+	// restoreSourceKindBackupSchedule is the kind of a BackupSchedule
+	// restore source.
+	restoreSourceKindBackupSchedule = "BackupSchedule"
+)
+
+const (
+	errFmtInvalidRestoreGroup = `invalid restore source apiGroup %q: must be "spaces.upbound.io"`
+	errFmtInvalidRestoreKind  = "invalid restore source kind %q: must be one of Backup or BackupSchedule"
+	errEmptyRestoreName       = "restore source name cannot be empty"
+	errRestoreSourceImmutable = "restore source is immutable"
+	errFmtFinishedAtInFuture  = "restore finishedAt %s is in the future"
+	errRestoreUnset           = "restore source can not be unset"
+	errRestoreSetAfterCreate  = "restore source can not be set after creation"
+	errFinishedAtImmutable    = "restore finishedAt is immutable once set"
+)
+
+// Validate checks that the restore source references a Backup or a
+// BackupSchedule, mirroring the CEL rules on the Restore source.
+func (r *Restore) Validate() error {
+	if g := ptr.Deref(r.Source.APIGroup, Group); g != Group {
+		return errors.Errorf(errFmtInvalidRestoreGroup, g)
+	}
+	switch r.Source.Kind {
+	case restoreSourceKindBackup, restoreSourceKindBackupSchedule:
+	default:
+		return errors.Errorf(errFmtInvalidRestoreKind, r.Source.Kind)
+	}
+	if r.Source.Name == "" {
+		return errors.New(errEmptyRestoreName)
+	}
+	return nil
+}
+
+// ValidateFinishedAt checks that the restore has not finished in the future,
+// which would indicate a bug in the system stamping FinishedAt. A FinishedAt
+// later than now by up to the supplied clock skew is tolerated. An unset
+// FinishedAt is valid.
+func (r *Restore) ValidateFinishedAt(now time.Time, skew time.Duration) error {
+	if r.FinishedAt == nil {
+		return nil
+	}
+	if r.FinishedAt.Time.After(now.Add(skew)) {
+		return errors.Errorf(errFmtFinishedAtInFuture, r.FinishedAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// GetRestore returns the restore configuration of this ControlPlane, or nil if
+// it is not restored from a backup.
+func (mg *ControlPlane) GetRestore() *Restore {
+	return mg.Spec.Restore
+}
+
+// SetRestore sets the restore configuration of this ControlPlane after
+// validating its source. The source of an already configured restore cannot be
+// changed. A nil Restore clears the restore configuration.
+//
+// Restoring is gated by the "EnableSharedBackup" feature gate, which must be
+// enabled in the Space the ControlPlane is created in.
+func (mg *ControlPlane) SetRestore(r *Restore) error {
+	if r == nil {
+		mg.Spec.Restore = nil
+		return nil
+	}
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if cur := mg.Spec.Restore; cur != nil && !equality.Semantic.DeepEqual(cur.Source, r.Source) {
+		return errors.New(errRestoreSourceImmutable)
+	}
+	mg.Spec.Restore = r
+	return nil
+}
+
+// EnforceRestoreImmutability checks an update from the supplied old spec to
+// this spec against the CEL rules guarding the restore configuration, so that
+// tooling operating outside of a cluster gets the same guarantees: a restore
+// can neither be set after creation nor unset, its source cannot change, and
+// its FinishedAt can be set once but not changed afterwards. A nil old spec,
+// i.e. a creation, is always allowed.
+func (s *ControlPlaneSpec) EnforceRestoreImmutability(old *ControlPlaneSpec) error {
+	if old == nil {
+		return nil
+	}
+	switch {
+	case old.Restore == nil && s.Restore == nil:
+		return nil
+	case old.Restore == nil:
+		return errors.New(errRestoreSetAfterCreate)
+	case s.Restore == nil:
+		return errors.New(errRestoreUnset)
+	}
+	if !equality.Semantic.DeepEqual(old.Restore.Source, s.Restore.Source) {
+		return errors.New(errRestoreSourceImmutable)
+	}
+	if old.Restore.FinishedAt != nil && !old.Restore.FinishedAt.Equal(s.Restore.FinishedAt) {
+		return errors.New(errFinishedAtImmutable)
+	}
+	return nil
+}
+
+// RestoreStuck returns true if this ControlPlane has been waiting for its
+// restore to complete for longer than the supplied timeout as of now. The
+// restore is considered to be waiting since the last transition of the
+// Restored condition, or since the creation of the ControlPlane if the
+// condition has not been reported yet. A restore that has completed or
+// failed is not stuck, nor is a ControlPlane without a restore configured.
+func (mg *ControlPlane) RestoreStuck(now time.Time, timeout time.Duration) bool {
+	if mg.Spec.Restore == nil {
+		return false
+	}
+	c := mg.GetCondition(ConditionTypeRestored)
+	if c.Status == corev1.ConditionTrue || c.Reason == ReasonRestoreFailed {
+		return false
+	}
+	since := c.LastTransitionTime
+	if since.IsZero() {
+		since = mg.CreationTimestamp
+	}
+	return now.Sub(since.Time) > timeout
+}
@@ -0,0 +1,272 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	xpcommonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/upbound/up-sdk-go/apis/common"
+)
+
+func TestSetRestore(t *testing.T) {
+	backup := &Restore{Source: common.TypedLocalObjectReference{Kind: "Backup", Name: "nightly"}}
+
+	type want struct {
+		restore *Restore
+		err     error
+	}
+	cases := map[string]struct {
+		reason   string
+		existing *Restore
+		restore  *Restore
+		want     want
+	}{
+		"Backup": {
+			reason:  "A restore from a Backup should be set.",
+			restore: backup,
+			want:    want{restore: backup},
+		},
+		"BackupScheduleWithGroup": {
+			reason:  "A restore from a BackupSchedule with an explicit API group should be set.",
+			restore: &Restore{Source: common.TypedLocalObjectReference{APIGroup: ptr.To(Group), Kind: "BackupSchedule", Name: "daily"}},
+			want:    want{restore: &Restore{Source: common.TypedLocalObjectReference{APIGroup: ptr.To(Group), Kind: "BackupSchedule", Name: "daily"}}},
+		},
+		"Clear": {
+			reason:   "A nil restore should clear the restore configuration.",
+			existing: backup,
+		},
+		"InvalidGroup": {
+			reason:  "A restore from a source in another API group should be rejected.",
+			restore: &Restore{Source: common.TypedLocalObjectReference{APIGroup: ptr.To("example.org"), Kind: "Backup", Name: "nightly"}},
+			want:    want{err: errors.Errorf(errFmtInvalidRestoreGroup, "example.org")},
+		},
+		"InvalidKind": {
+			reason:  "A restore from a source that is not a Backup or BackupSchedule should be rejected.",
+			restore: &Restore{Source: common.TypedLocalObjectReference{Kind: "SharedBackup", Name: "nightly"}},
+			want:    want{err: errors.Errorf(errFmtInvalidRestoreKind, "SharedBackup")},
+		},
+		"EmptyName": {
+			reason:  "A restore from a source without a name should be rejected.",
+			restore: &Restore{Source: common.TypedLocalObjectReference{Kind: "Backup"}},
+			want:    want{err: errors.New(errEmptyRestoreName)},
+		},
+		"ImmutableSource": {
+			reason:   "Changing the source of a configured restore should be rejected.",
+			existing: backup,
+			restore:  &Restore{Source: common.TypedLocalObjectReference{Kind: "Backup", Name: "weekly"}},
+			want:     want{restore: backup, err: errors.New(errRestoreSourceImmutable)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{Spec: ControlPlaneSpec{Restore: tc.existing}}
+			err := mg.SetRestore(tc.restore)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetRestore(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.restore, mg.GetRestore()); diff != "" {
+				t.Errorf("\n%s\nGetRestore(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateFinishedAt(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	skew := 5 * time.Second
+
+	cases := map[string]struct {
+		reason     string
+		finishedAt *metav1.Time
+		want       error
+	}{
+		"Unset": {
+			reason: "An unfinished restore should be valid.",
+		},
+		"Past": {
+			reason:     "A restore that finished in the past should be valid.",
+			finishedAt: ptr.To(metav1.NewTime(now.Add(-time.Hour))),
+		},
+		"Now": {
+			reason:     "A restore that finished now should be valid.",
+			finishedAt: ptr.To(metav1.NewTime(now)),
+		},
+		"WithinSkew": {
+			reason:     "A restore that finished in the future within the clock skew should be valid.",
+			finishedAt: ptr.To(metav1.NewTime(now.Add(skew))),
+		},
+		"BeyondSkew": {
+			reason:     "A restore that finished in the future beyond the clock skew should be invalid.",
+			finishedAt: ptr.To(metav1.NewTime(now.Add(skew + time.Second))),
+			want:       errors.Errorf(errFmtFinishedAtInFuture, "2024-05-01T12:00:06Z"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Restore{FinishedAt: tc.finishedAt}
+			err := r.ValidateFinishedAt(now, skew)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateFinishedAt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnforceRestoreImmutability(t *testing.T) {
+	source := common.TypedLocalObjectReference{Kind: "Backup", Name: "nightly"}
+	finishedAt := &metav1.Time{Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	cases := map[string]struct {
+		reason string
+		old    *ControlPlaneSpec
+		new    ControlPlaneSpec
+		want   error
+	}{
+		"Create": {
+			reason: "Setting the restore source on creation should be allowed.",
+			new:    ControlPlaneSpec{Restore: &Restore{Source: source}},
+		},
+		"NoRestore": {
+			reason: "Updating a spec without a restore should be allowed.",
+			old:    &ControlPlaneSpec{},
+		},
+		"Unchanged": {
+			reason: "Leaving the restore source unchanged should be allowed.",
+			old:    &ControlPlaneSpec{Restore: &Restore{Source: source}},
+			new:    ControlPlaneSpec{Restore: &Restore{Source: source}},
+		},
+		"SetAfterCreation": {
+			reason: "Setting the restore source after creation should be rejected.",
+			old:    &ControlPlaneSpec{},
+			new:    ControlPlaneSpec{Restore: &Restore{Source: source}},
+			want:   errors.New(errRestoreSetAfterCreate),
+		},
+		"Unset": {
+			reason: "Unsetting the restore source should be rejected.",
+			old:    &ControlPlaneSpec{Restore: &Restore{Source: source}},
+			want:   errors.New(errRestoreUnset),
+		},
+		"SourceChanged": {
+			reason: "Changing the restore source should be rejected.",
+			old:    &ControlPlaneSpec{Restore: &Restore{Source: source}},
+			new:    ControlPlaneSpec{Restore: &Restore{Source: common.TypedLocalObjectReference{Kind: "Backup", Name: "weekly"}}},
+			want:   errors.New(errRestoreSourceImmutable),
+		},
+		"FinishedAtSet": {
+			reason: "Setting FinishedAt for the first time should be allowed.",
+			old:    &ControlPlaneSpec{Restore: &Restore{Source: source}},
+			new:    ControlPlaneSpec{Restore: &Restore{Source: source, FinishedAt: finishedAt}},
+		},
+		"FinishedAtChanged": {
+			reason: "Changing FinishedAt once set should be rejected.",
+			old:    &ControlPlaneSpec{Restore: &Restore{Source: source, FinishedAt: finishedAt}},
+			new:    ControlPlaneSpec{Restore: &Restore{Source: source, FinishedAt: &metav1.Time{Time: finishedAt.Add(time.Hour)}}},
+			want:   errors.New(errFinishedAtImmutable),
+		},
+		"FinishedAtUnset": {
+			reason: "Unsetting FinishedAt once set should be rejected.",
+			old:    &ControlPlaneSpec{Restore: &Restore{Source: source, FinishedAt: finishedAt}},
+			new:    ControlPlaneSpec{Restore: &Restore{Source: source}},
+			want:   errors.New(errFinishedAtImmutable),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.new.EnforceRestoreImmutability(tc.old)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nEnforceRestoreImmutability(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRestoreStuck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-2 * time.Hour))
+	restore := &Restore{Source: common.TypedLocalObjectReference{Kind: "Backup", Name: "nightly"}}
+	restored := func(status corev1.ConditionStatus, reason xpcommonv1.ConditionReason, ago time.Duration) xpcommonv1.Condition {
+		return xpcommonv1.Condition{
+			Type:               ConditionTypeRestored,
+			Status:             status,
+			Reason:             reason,
+			LastTransitionTime: metav1.NewTime(now.Add(-ago)),
+		}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		restore *Restore
+		conds   []xpcommonv1.Condition
+		want    bool
+	}{
+		"NoRestore": {
+			reason: "A ControlPlane without a restore configured should not be stuck.",
+			conds:  []xpcommonv1.Condition{restored(corev1.ConditionFalse, "Restoring", time.Hour)},
+		},
+		"NoConditionBeyondTimeout": {
+			reason:  "A restore without a Restored condition should be stuck if the ControlPlane was created longer than the timeout ago.",
+			restore: restore,
+			want:    true,
+		},
+		"PendingWithinTimeout": {
+			reason:  "A restore pending for less than the timeout should not be stuck.",
+			restore: restore,
+			conds:   []xpcommonv1.Condition{restored(corev1.ConditionFalse, "Restoring", 10*time.Minute)},
+		},
+		"PendingBeyondTimeout": {
+			reason:  "A restore pending for longer than the timeout should be stuck.",
+			restore: restore,
+			conds:   []xpcommonv1.Condition{restored(corev1.ConditionFalse, "Restoring", time.Hour)},
+			want:    true,
+		},
+		"UnknownBeyondTimeout": {
+			reason:  "A restore with an Unknown Restored condition for longer than the timeout should be stuck.",
+			restore: restore,
+			conds:   []xpcommonv1.Condition{restored(corev1.ConditionUnknown, "", time.Hour)},
+			want:    true,
+		},
+		"Completed": {
+			reason:  "A completed restore should not be stuck.",
+			restore: restore,
+			conds:   []xpcommonv1.Condition{restored(corev1.ConditionTrue, ReasonRestoreCompleted, time.Hour)},
+		},
+		"Failed": {
+			reason:  "A failed restore should not be stuck.",
+			restore: restore,
+			conds:   []xpcommonv1.Condition{restored(corev1.ConditionFalse, ReasonRestoreFailed, time.Hour)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+				Spec:       ControlPlaneSpec{Restore: tc.restore},
+			}
+			mg.SetConditions(tc.conds...)
+			if got := mg.RestoreStuck(now, 30*time.Minute); got != tc.want {
+				t.Errorf("\n%s\nRestoreStuck(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ControlPlaneShortNames are the short names of the ControlPlane resource, as
+// declared by the shortName marker of the ControlPlane type.
+var ControlPlaneShortNames = []string{"ctp", "ctps"}
+
+// ResolveShortName returns the GroupKind of the resource with the supplied
+// short name, so that tooling parsing user input can accept the same aliases
+// as kubectl. Short names are matched case-insensitively, as kubectl does. It
+// returns false if the short name is not known.
+func ResolveShortName(s string) (schema.GroupKind, bool) {
+	for _, n := range ControlPlaneShortNames {
+		if strings.EqualFold(s, n) {
+			return schema.GroupKind{Group: Group, Kind: ControlPlaneKind}, true
+		}
+	}
+	return schema.GroupKind{}, false
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestShortNamesInSyncWithMarkers checks that ControlPlaneShortNames are
+// exactly the short names declared by the shortName marker of the
+// ControlPlane type.
+func TestShortNamesInSyncWithMarkers(t *testing.T) {
+	src, err := os.ReadFile("controlplane_types.go")
+	if err != nil {
+		t.Fatalf("cannot read the ControlPlane types: %v", err)
+	}
+	m := regexp.MustCompile(`\+kubebuilder:resource:.*shortName=([\w;]+)`).FindStringSubmatch(string(src))
+	if m == nil {
+		t.Fatal("cannot find the shortName marker of the ControlPlane type")
+	}
+	if diff := cmp.Diff(strings.Split(m[1], ";"), ControlPlaneShortNames); diff != "" {
+		t.Errorf("ControlPlaneShortNames are not in sync with the shortName marker: -marker, +ControlPlaneShortNames:\n%s", diff)
+	}
+}
+
+func TestResolveShortName(t *testing.T) {
+	type want struct {
+		gk schema.GroupKind
+		ok bool
+	}
+	ctp := want{gk: schema.GroupKind{Group: "spaces.upbound.io", Kind: "ControlPlane"}, ok: true}
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   want
+	}{
+		"Singular": {
+			reason: "The ctp short name should resolve to the ControlPlane kind.",
+			name:   "ctp",
+			want:   ctp,
+		},
+		"Plural": {
+			reason: "The ctps short name should resolve to the ControlPlane kind.",
+			name:   "ctps",
+			want:   ctp,
+		},
+		"UpperCase": {
+			reason: "Short names should be matched case-insensitively.",
+			name:   "CTP",
+			want:   ctp,
+		},
+		"Unknown": {
+			reason: "An unknown short name should not resolve.",
+			name:   "cp",
+		},
+		"Empty": {
+			reason: "An empty short name should not resolve.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gk, ok := ResolveShortName(tc.name)
+			if diff := cmp.Diff(tc.want, want{gk: gk, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nResolveShortName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// DesiredScale returns the number of replicas the Crossplane and provider
+// workloads of a ControlPlane in this state should be scaled to: zero if the
+// ControlPlane is Paused, and one otherwise. A nil state defaults to Running.
+func (s *CrossplaneState) DesiredScale() int32 {
+	if s != nil && *s == CrossplaneStatePaused {
+		return 0
+	}
+	return 1
+}
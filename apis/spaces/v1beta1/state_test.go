@@ -0,0 +1,51 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+)
+
+func TestDesiredScale(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		state  *CrossplaneState
+		want   int32
+	}{
+		"Nil": {
+			reason: "A nil state defaults to Running and should scale to one replica.",
+			want:   1,
+		},
+		"Running": {
+			reason: "A Running ControlPlane should scale to one replica.",
+			state:  ptr.To(CrossplaneStateRunning),
+			want:   1,
+		},
+		"Paused": {
+			reason: "A Paused ControlPlane should scale to zero replicas.",
+			state:  ptr.To(CrossplaneStatePaused),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.state.DesiredScale()); diff != "" {
+				t.Errorf("\n%s\nDesiredScale(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
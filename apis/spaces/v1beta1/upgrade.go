@@ -0,0 +1,197 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	errFmtParseVersion    = "cannot parse version %q"
+	errFmtUnknownChannel  = "unknown upgrade channel %q"
+	errFmtNoChannelTarget = "no available version for upgrade channel %q"
+	errFmtParseConstraint = "cannot parse version constraint %q"
+)
+
+// EffectiveChannel returns the upgrade channel of this spec, defaulting to
+// Stable as the API server does when no channel is set.
+func (s *CrossplaneSpec) EffectiveChannel() CrossplaneUpgradeChannel {
+	if s.AutoUpgradeSpec == nil || s.AutoUpgradeSpec.Channel == nil {
+		return CrossplaneUpgradeStable
+	}
+	return *s.AutoUpgradeSpec.Channel
+}
+
+// Risk returns the ordinal risk of the upgrade channel, from 0 for None, which
+// never upgrades, to 3 for Rapid, which upgrades to the latest minor version.
+// Unknown channels have a risk of -1.
+func (c CrossplaneUpgradeChannel) Risk() int {
+	switch c {
+	case CrossplaneUpgradeNone:
+		return 0
+	case CrossplaneUpgradePatch:
+		return 1
+	case CrossplaneUpgradeStable:
+		return 2
+	case CrossplaneUpgradeRapid:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// RiskierThan returns true if the upgrade channel is riskier than the other.
+func (c CrossplaneUpgradeChannel) RiskierThan(other CrossplaneUpgradeChannel) bool {
+	return c.Risk() > other.Risk()
+}
+
+// ResolveChannelTarget returns the version among the available versions that
+// a control plane currently at the current version would be upgraded to by
+// the supplied channel:
+//   - None: the current version, i.e., no upgrade.
+//   - Patch: the latest patch version of the current minor version.
+//   - Stable: the latest patch version of minor version N-1, where N is the
+//     latest available minor version. N-1 is the highest available minor
+//     version below N, which belongs to the previous major version if N is
+//     the first minor version of its major version.
+//   - Rapid: the latest patch version of the latest available minor version.
+func ResolveChannelTarget(channel CrossplaneUpgradeChannel, current string, available []string) (string, error) {
+	if channel == CrossplaneUpgradeNone {
+		return current, nil
+	}
+	versions, err := parseVersions(available)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", errors.Errorf(errFmtNoChannelTarget, channel)
+	}
+	// versions are sorted in descending order.
+	latest := versions[0]
+
+	var match func(v *semver.Version) bool
+	switch channel {
+	case CrossplaneUpgradePatch:
+		cur, err := semver.NewVersion(current)
+		if err != nil {
+			return "", errors.Wrapf(err, errFmtParseVersion, current)
+		}
+		match = func(v *semver.Version) bool {
+			return v.Major() == cur.Major() && v.Minor() == cur.Minor()
+		}
+	case CrossplaneUpgradeStable:
+		// As versions are sorted in descending order, the first version
+		// with a different major or minor version than the latest one is
+		// the latest patch version of minor version N-1.
+		match = func(v *semver.Version) bool {
+			return v.Major() != latest.Major() || v.Minor() != latest.Minor()
+		}
+	case CrossplaneUpgradeRapid:
+		match = func(v *semver.Version) bool {
+			return v.Major() == latest.Major() && v.Minor() == latest.Minor()
+		}
+	default:
+		return "", errors.Errorf(errFmtUnknownChannel, channel)
+	}
+	for _, v := range versions {
+		if match(v) {
+			return v.Original(), nil
+		}
+	}
+	return "", errors.Errorf(errFmtNoChannelTarget, channel)
+}
+
+// UpgradeNeeded returns whether a control plane currently at the current
+// version needs to be upgraded according to the supplied channel, and the
+// version it should be upgraded to if so. An upgrade is never needed for the
+// None channel, and a channel never causes a downgrade.
+func UpgradeNeeded(channel CrossplaneUpgradeChannel, current string, available []string) (bool, string, error) {
+	if channel == CrossplaneUpgradeNone {
+		return false, "", nil
+	}
+	cur, err := semver.NewVersion(current)
+	if err != nil {
+		return false, "", errors.Wrapf(err, errFmtParseVersion, current)
+	}
+	target, err := ResolveChannelTarget(channel, current, available)
+	if err != nil {
+		return false, "", err
+	}
+	t, err := semver.NewVersion(target)
+	if err != nil {
+		return false, "", errors.Wrapf(err, errFmtParseVersion, target)
+	}
+	if !t.GreaterThan(cur) {
+		return false, "", nil
+	}
+	return true, target, nil
+}
+
+// CrossesMinor returns true if moving from the current to the target version
+// changes the major or the minor version, e.g., to require an approval for
+// minor upgrades while approving patch upgrades automatically. Moving between
+// the prereleases or the patch versions of a minor version does not cross it.
+func CrossesMinor(current, target string) (bool, error) {
+	cur, err := semver.NewVersion(current)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtParseVersion, current)
+	}
+	t, err := semver.NewVersion(target)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtParseVersion, target)
+	}
+	return cur.Major() != t.Major() || cur.Minor() != t.Minor(), nil
+}
+
+// SatisfiesConstraint returns true if the supplied version satisfies the
+// VersionConstraint. Any version satisfies an unset constraint. As all the
+// Crossplane versions served by Spaces are prereleases with an "-up.N" suffix,
+// which semantic version constraints without a prerelease would never match,
+// a version also satisfies the constraint if its release part does, e.g.,
+// 1.15.2-up.1 satisfies ">= 1.14, < 1.16". Constraints pinning a prerelease,
+// such as "1.15.2-up.1", are still matched against the full version.
+func (s *CrossplaneAutoUpgradeSpec) SatisfiesConstraint(version string) (bool, error) {
+	if s == nil || s.VersionConstraint == nil {
+		return true, nil
+	}
+	c, err := semver.NewConstraint(*s.VersionConstraint)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtParseConstraint, *s.VersionConstraint)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtParseVersion, version)
+	}
+	release := semver.New(v.Major(), v.Minor(), v.Patch(), "", "")
+	return c.Check(v) || c.Check(release), nil
+}
+
+// parseVersions parses the supplied versions and returns them sorted in
+// descending order.
+func parseVersions(versions []string) ([]*semver.Version, error) {
+	parsed := make([]*semver.Version, 0, len(versions))
+	for _, s := range versions {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseVersion, s)
+		}
+		parsed = append(parsed, v)
+	}
+	sort.Sort(sort.Reverse(semver.Collection(parsed)))
+	return parsed, nil
+}
@@ -0,0 +1,354 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+)
+
+var testAvailableVersions = []string{
+	"1.13.2-up.3",
+	"1.14.1-up.1",
+	"1.14.8-up.1",
+	"1.15.0-up.1",
+	"1.15.2-up.1",
+	"1.14.5-up.1",
+}
+
+func TestResolveChannelTarget(t *testing.T) {
+	type args struct {
+		channel   CrossplaneUpgradeChannel
+		current   string
+		available []string
+	}
+	type want struct {
+		target string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"None": {
+			reason: "The None channel should resolve to the current version.",
+			args:   args{channel: CrossplaneUpgradeNone, current: "1.14.1-up.1", available: testAvailableVersions},
+			want:   want{target: "1.14.1-up.1"},
+		},
+		"Patch": {
+			reason: "The Patch channel should resolve to the latest patch of the current minor version.",
+			args:   args{channel: CrossplaneUpgradePatch, current: "1.14.1-up.1", available: testAvailableVersions},
+			want:   want{target: "1.14.8-up.1"},
+		},
+		"Stable": {
+			reason: "The Stable channel should resolve to the latest patch of the N-1 minor version.",
+			args:   args{channel: CrossplaneUpgradeStable, current: "1.13.2-up.3", available: testAvailableVersions},
+			want:   want{target: "1.14.8-up.1"},
+		},
+		"Rapid": {
+			reason: "The Rapid channel should resolve to the latest patch of the latest minor version.",
+			args:   args{channel: CrossplaneUpgradeRapid, current: "1.13.2-up.3", available: testAvailableVersions},
+			want:   want{target: "1.15.2-up.1"},
+		},
+		"StableAcrossMajor": {
+			reason: "The Stable channel should resolve to the latest patch of the previous major version's last minor version if N is the first minor version of its major version.",
+			args:   args{channel: CrossplaneUpgradeStable, current: "1.20.0-up.1", available: []string{"1.19.4-up.1", "1.20.0-up.1", "1.20.1-up.1", "2.0.0-up.1", "2.0.1-up.1"}},
+			want:   want{target: "1.20.1-up.1"},
+		},
+		"StableSkippedMinor": {
+			reason: "The Stable channel should resolve to the highest available minor version below N even if N-1 is not available.",
+			args:   args{channel: CrossplaneUpgradeStable, current: "1.13.2-up.3", available: []string{"1.13.2-up.3", "1.13.4-up.1", "1.15.0-up.1"}},
+			want:   want{target: "1.13.4-up.1"},
+		},
+		"NoStableTarget": {
+			reason: "The Stable channel should fail to resolve if there is no N-1 minor version.",
+			args:   args{channel: CrossplaneUpgradeStable, available: []string{"1.15.0-up.1"}},
+			want:   want{err: errors.Errorf(errFmtNoChannelTarget, CrossplaneUpgradeStable)},
+		},
+		"UnknownChannel": {
+			reason: "An unknown channel should fail to resolve.",
+			args:   args{channel: "Nightly", available: testAvailableVersions},
+			want:   want{err: errors.Errorf(errFmtUnknownChannel, "Nightly")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			target, err := ResolveChannelTarget(tc.args.channel, tc.args.current, tc.args.available)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveChannelTarget(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.target, target); diff != "" {
+				t.Errorf("\n%s\nResolveChannelTarget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpgradeNeeded(t *testing.T) {
+	type args struct {
+		channel CrossplaneUpgradeChannel
+		current string
+	}
+	type want struct {
+		needed bool
+		target string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"None": {
+			reason: "An upgrade should never be needed for the None channel.",
+			args:   args{channel: CrossplaneUpgradeNone, current: "1.13.2-up.3"},
+		},
+		"PatchAvailable": {
+			reason: "An upgrade should be needed if a newer patch version is available.",
+			args:   args{channel: CrossplaneUpgradePatch, current: "1.14.5-up.1"},
+			want:   want{needed: true, target: "1.14.8-up.1"},
+		},
+		"AlreadyOnTarget": {
+			reason: "An upgrade should not be needed if the current version is the channel's target.",
+			args:   args{channel: CrossplaneUpgradeRapid, current: "1.15.2-up.1"},
+		},
+		"NoDowngrade": {
+			reason: "An upgrade should not be needed if the current version is newer than the channel's target.",
+			args:   args{channel: CrossplaneUpgradeStable, current: "1.15.0-up.1"},
+		},
+		"InvalidCurrent": {
+			reason: "An invalid current version should return an error.",
+			args:   args{channel: CrossplaneUpgradeRapid, current: "latest"},
+			want:   want{err: errors.Wrapf(semver.ErrInvalidSemVer, errFmtParseVersion, "latest")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			needed, target, err := UpgradeNeeded(tc.args.channel, tc.args.current, testAvailableVersions)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUpgradeNeeded(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.needed, needed); diff != "" {
+				t.Errorf("\n%s\nUpgradeNeeded(...): -want needed, +got needed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.target, target); diff != "" {
+				t.Errorf("\n%s\nUpgradeNeeded(...): -want target, +got target:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCrossesMinor(t *testing.T) {
+	type args struct {
+		current string
+		target  string
+	}
+	type want struct {
+		crosses bool
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Same": {
+			reason: "The same version should not cross a minor version.",
+			args:   args{current: "1.14.5-up.1", target: "1.14.5-up.1"},
+		},
+		"Prerelease": {
+			reason: "A newer prerelease of the same version should not cross a minor version.",
+			args:   args{current: "1.14.5-up.1", target: "1.14.5-up.2"},
+		},
+		"Patch": {
+			reason: "A patch upgrade should not cross a minor version.",
+			args:   args{current: "1.14.5-up.1", target: "1.14.8-up.1"},
+		},
+		"Minor": {
+			reason: "A minor upgrade should cross a minor version.",
+			args:   args{current: "1.14.8-up.1", target: "1.15.0-up.1"},
+			want:   want{crosses: true},
+		},
+		"MinorDowngrade": {
+			reason: "A minor downgrade should cross a minor version.",
+			args:   args{current: "1.15.0-up.1", target: "1.14.8-up.1"},
+			want:   want{crosses: true},
+		},
+		"Major": {
+			reason: "A major upgrade with the same minor version should cross a minor version.",
+			args:   args{current: "1.15.2", target: "2.15.0"},
+			want:   want{crosses: true},
+		},
+		"InvalidCurrent": {
+			reason: "An unparsable current version should return an error.",
+			args:   args{current: "latest", target: "1.15.0"},
+			want:   want{err: errors.Wrapf(semver.ErrInvalidSemVer, errFmtParseVersion, "latest")},
+		},
+		"InvalidTarget": {
+			reason: "An unparsable target version should return an error.",
+			args:   args{current: "1.15.0", target: "next"},
+			want:   want{err: errors.Wrapf(semver.ErrInvalidSemVer, errFmtParseVersion, "next")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			crosses, err := CrossesMinor(tc.args.current, tc.args.target)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCrossesMinor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.crosses, crosses); diff != "" {
+				t.Errorf("\n%s\nCrossesMinor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSatisfiesConstraint(t *testing.T) {
+	type want struct {
+		ok  bool
+		err error
+	}
+	cases := map[string]struct {
+		reason  string
+		spec    *CrossplaneAutoUpgradeSpec
+		version string
+		want    want
+	}{
+		"NilSpec": {
+			reason:  "Any version should satisfy a nil spec.",
+			version: "1.15.0-up.1",
+			want:    want{ok: true},
+		},
+		"NoConstraint": {
+			reason:  "Any version should satisfy an unset constraint.",
+			spec:    &CrossplaneAutoUpgradeSpec{},
+			version: "1.15.0-up.1",
+			want:    want{ok: true},
+		},
+		"Satisfied": {
+			reason:  "A version within the constraint should satisfy it.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To(">= 1.14.0-0, < 1.15.0-0")},
+			version: "1.14.8-up.1",
+			want:    want{ok: true},
+		},
+		"NotSatisfied": {
+			reason:  "A version outside the constraint should not satisfy it.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To(">= 1.14.0-0, < 1.15.0-0")},
+			version: "1.15.0-up.1",
+		},
+		"ReleaseConstraint": {
+			reason:  "A prerelease version should satisfy a constraint without prereleases if its release part does.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To(">= 1.14, < 1.16")},
+			version: "1.15.2-up.1",
+			want:    want{ok: true},
+		},
+		"ReleaseConstraintLowerBound": {
+			reason:  "A prerelease of the lower bound should satisfy a constraint without prereleases.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To(">= 1.14, < 1.16")},
+			version: "1.14.8-up.1",
+			want:    want{ok: true},
+		},
+		"ReleaseConstraintNotSatisfied": {
+			reason:  "A prerelease version should not satisfy a constraint its release part does not satisfy.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To(">= 1.14, < 1.16")},
+			version: "1.16.0-up.1",
+		},
+		"PinnedPrerelease": {
+			reason:  "A constraint pinning a prerelease should only be satisfied by that prerelease.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To("1.15.2-up.1")},
+			version: "1.15.2-up.2",
+		},
+		"InvalidConstraint": {
+			reason:  "An invalid constraint should return an error.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To("~> one")},
+			version: "1.15.0-up.1",
+			want:    want{err: errors.Wrapf(errors.New(`improper constraint: ~> one`), errFmtParseConstraint, "~> one")},
+		},
+		"InvalidVersion": {
+			reason:  "An invalid version should return an error.",
+			spec:    &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To(">= 1.14")},
+			version: "latest",
+			want:    want{err: errors.Wrapf(semver.ErrInvalidSemVer, errFmtParseVersion, "latest")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ok, err := tc.spec.SatisfiesConstraint(tc.version)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSatisfiesConstraint(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nSatisfiesConstraint(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestChannelRisk(t *testing.T) {
+	// ordered lists the known channels from the least to the most risky.
+	ordered := []CrossplaneUpgradeChannel{CrossplaneUpgradeNone, CrossplaneUpgradePatch, CrossplaneUpgradeStable, CrossplaneUpgradeRapid}
+	for i, c := range ordered {
+		if diff := cmp.Diff(i, c.Risk()); diff != "" {
+			t.Errorf("\n%s.Risk(): -want, +got:\n%s", c, diff)
+		}
+		for j, other := range ordered {
+			if diff := cmp.Diff(i > j, c.RiskierThan(other)); diff != "" {
+				t.Errorf("\n%s.RiskierThan(%s): -want, +got:\n%s", c, other, diff)
+			}
+		}
+	}
+	if diff := cmp.Diff(-1, CrossplaneUpgradeChannel("Nightly").Risk()); diff != "" {
+		t.Errorf("\nUnknown channel Risk(): -want, +got:\n%s", diff)
+	}
+	if CrossplaneUpgradeChannel("Nightly").RiskierThan(CrossplaneUpgradeNone) {
+		t.Errorf("\nUnknown channel RiskierThan(None): want false, got true")
+	}
+}
+
+func TestEffectiveChannel(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   CrossplaneSpec
+		want   CrossplaneUpgradeChannel
+	}{
+		"NoAutoUpgrade": {
+			reason: "A spec without an auto-upgrade configuration should use the Stable channel.",
+			want:   CrossplaneUpgradeStable,
+		},
+		"NoChannel": {
+			reason: "A spec without a channel should use the Stable channel.",
+			spec:   CrossplaneSpec{AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{}},
+			want:   CrossplaneUpgradeStable,
+		},
+		"Channel": {
+			reason: "A spec with a channel should use it.",
+			spec:   CrossplaneSpec{AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeNone)}},
+			want:   CrossplaneUpgradeNone,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.spec.EffectiveChannel()); diff != "" {
+				t.Errorf("\n%s\nEffectiveChannel(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
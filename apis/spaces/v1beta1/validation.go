@@ -0,0 +1,136 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	errVersionRequiredForChannelNone = `"version" cannot be empty when upgrade channel is "None"`
+	errFmtInvalidControlPlaneName    = "invalid control plane name %q: %s"
+	errFmtInvalidCrossplaneState     = "invalid crossplane state %q: must be one of Running or Paused"
+	errConstraintWithChannelNone     = `"versionConstraint" cannot be set when upgrade channel is "None"`
+	errFmtDowngrade                  = "crossplane version cannot be downgraded from %q to %q"
+	errFmtExactConstraintWithRapid   = `"versionConstraint" %q pins an exact version, which cannot be combined with the "Rapid" upgrade channel`
+)
+
+// ValidateChannelVersionConsistency checks that a Crossplane version is pinned
+// when auto-upgrades are disabled with the None channel. It mirrors the CEL
+// rule on ControlPlaneSpec for clients that do not go through the API server.
+func (s *CrossplaneSpec) ValidateChannelVersionConsistency() error {
+	if s.AutoUpgradeSpec == nil || ptr.Deref(s.AutoUpgradeSpec.Channel, "") != CrossplaneUpgradeNone {
+		return nil
+	}
+	if ptr.Deref(s.Version, "") == "" {
+		return errors.New(errVersionRequiredForChannelNone)
+	}
+	return nil
+}
+
+// ValidateControlPlaneName checks that the supplied name is a valid
+// ControlPlane name. ControlPlane names must be RFC 1123 DNS labels, i.e.,
+// at most 63 characters long, consisting only of lower case alphanumeric
+// characters or '-', and starting and ending with an alphanumeric character.
+func ValidateControlPlaneName(name string) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return errors.Errorf(errFmtInvalidControlPlaneName, name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateName checks that this ControlPlane has a valid name.
+func (mg *ControlPlane) ValidateName() error {
+	return ValidateControlPlaneName(mg.GetName())
+}
+
+// Validate checks that the CrossplaneState is one of the states accepted by
+// the API server. A nil CrossplaneState is valid and defaults to Running,
+// whereas an empty state is rejected by the API server and is thus invalid.
+func (s *CrossplaneState) Validate() error {
+	if s == nil {
+		return nil
+	}
+	switch *s {
+	case CrossplaneStateRunning, CrossplaneStatePaused:
+		return nil
+	default:
+		return errors.Errorf(errFmtInvalidCrossplaneState, *s)
+	}
+}
+
+// Validate checks that the upgrade channel and the version constraint of this
+// CrossplaneAutoUpgradeSpec are consistent. A version constraint cannot be
+// combined with the None channel, which disables auto-upgrades, and it cannot
+// pin an exact version when combined with the Rapid channel, which always
+// tracks the latest minor release.
+func (s *CrossplaneAutoUpgradeSpec) Validate() error {
+	if s == nil || s.VersionConstraint == nil {
+		return nil
+	}
+	constraint := *s.VersionConstraint
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		return errors.Wrapf(err, errFmtParseConstraint, constraint)
+	}
+	switch ptr.Deref(s.Channel, "") {
+	case CrossplaneUpgradeNone:
+		return errors.New(errConstraintWithChannelNone)
+	case CrossplaneUpgradeRapid:
+		if isExactConstraint(constraint) {
+			return errors.Errorf(errFmtExactConstraintWithRapid, constraint)
+		}
+	}
+	return nil
+}
+
+// isExactConstraint returns true if the supplied constraint matches a single,
+// fully specified version, e.g., "1.15.0" or "= 1.15.0".
+func isExactConstraint(constraint string) bool {
+	c := strings.TrimSpace(constraint)
+	c = strings.TrimSpace(strings.TrimLeft(c, "="))
+	_, err := semver.StrictNewVersion(c)
+	return err == nil
+}
+
+// ValidateNoDowngrade checks that the Crossplane version of this spec is not
+// lower than the version of the supplied old spec, as downgrading Crossplane
+// is not safe. An unset version on either side, e.g., because upgrades are
+// driven by the upgrade channel, is not considered a downgrade.
+func (s *CrossplaneSpec) ValidateNoDowngrade(old *CrossplaneSpec) error {
+	if old == nil {
+		return nil
+	}
+	oldVersion, newVersion := ptr.Deref(old.Version, ""), ptr.Deref(s.Version, "")
+	if oldVersion == "" || newVersion == "" {
+		return nil
+	}
+	ov, err := semver.NewVersion(oldVersion)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseVersion, oldVersion)
+	}
+	nv, err := semver.NewVersion(newVersion)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseVersion, newVersion)
+	}
+	if nv.LessThan(ov) {
+		return errors.Errorf(errFmtDowngrade, oldVersion, newVersion)
+	}
+	return nil
+}
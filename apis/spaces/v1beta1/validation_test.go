@@ -0,0 +1,309 @@
+// Copyright 2024 Upbound Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateChannelVersionConsistency(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   CrossplaneSpec
+		want   error
+	}{
+		"NoAutoUpgrade": {
+			reason: "A spec without an auto-upgrade configuration should be valid.",
+		},
+		"NilChannel": {
+			reason: "A spec without a channel should be valid as the channel defaults to Stable.",
+			spec: CrossplaneSpec{
+				AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{},
+			},
+		},
+		"StableWithoutVersion": {
+			reason: "A spec with an auto-upgrading channel does not need a version.",
+			spec: CrossplaneSpec{
+				AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeStable)},
+			},
+		},
+		"NoneWithVersion": {
+			reason: "A spec with the None channel and a version should be valid.",
+			spec: CrossplaneSpec{
+				Version:         ptr.To("1.15.0-up.1"),
+				AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeNone)},
+			},
+		},
+		"NoneWithoutVersion": {
+			reason: "A spec with the None channel and no version should be invalid.",
+			spec: CrossplaneSpec{
+				AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeNone)},
+			},
+			want: errors.New(errVersionRequiredForChannelNone),
+		},
+		"NoneWithEmptyVersion": {
+			reason: "A spec with the None channel and an empty version should be invalid.",
+			spec: CrossplaneSpec{
+				Version:         ptr.To(""),
+				AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeNone)},
+			},
+			want: errors.New(errVersionRequiredForChannelNone),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.spec.ValidateChannelVersionConsistency()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateChannelVersionConsistency(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateControlPlaneName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		name   string
+		valid  bool
+	}{
+		"Valid": {
+			reason: "A lower case alphanumeric name should be valid.",
+			name:   "ctp-1",
+			valid:  true,
+		},
+		"SingleCharacter": {
+			reason: "A single character name should be valid.",
+			name:   "a",
+			valid:  true,
+		},
+		"MaxLength": {
+			reason: "A 63 character name should be valid.",
+			name:   strings.Repeat("a", 63),
+			valid:  true,
+		},
+		"TooLong": {
+			reason: "A 64 character name should be invalid.",
+			name:   strings.Repeat("a", 64),
+		},
+		"Empty": {
+			reason: "An empty name should be invalid.",
+		},
+		"UpperCase": {
+			reason: "A name with upper case characters should be invalid.",
+			name:   "Ctp",
+		},
+		"Dot": {
+			reason: "A name with a dot should be invalid.",
+			name:   "ctp.example",
+		},
+		"Underscore": {
+			reason: "A name with an underscore should be invalid.",
+			name:   "my_ctp",
+		},
+		"LeadingDash": {
+			reason: "A name starting with a dash should be invalid.",
+			name:   "-ctp",
+		},
+		"TrailingDash": {
+			reason: "A name ending with a dash should be invalid.",
+			name:   "ctp-",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctp := &ControlPlane{ObjectMeta: metav1.ObjectMeta{Name: tc.name}}
+			err := ctp.ValidateName()
+			if (err == nil) != tc.valid {
+				t.Errorf("\n%s\nValidateName(): want valid %t, got error: %v", tc.reason, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestCrossplaneStateValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		state  *CrossplaneState
+		want   error
+	}{
+		"Nil": {
+			reason: "A nil state should be valid as it defaults to Running.",
+		},
+		"Running": {
+			reason: "The Running state should be valid.",
+			state:  ptr.To(CrossplaneStateRunning),
+		},
+		"Paused": {
+			reason: "The Paused state should be valid.",
+			state:  ptr.To(CrossplaneStatePaused),
+		},
+		"Empty": {
+			reason: "An empty state should be invalid as it is rejected by the API server.",
+			state:  ptr.To(CrossplaneState("")),
+			want:   errors.Errorf(errFmtInvalidCrossplaneState, ""),
+		},
+		"Unknown": {
+			reason: "An unknown state should be invalid.",
+			state:  ptr.To(CrossplaneState("Stopped")),
+			want:   errors.Errorf(errFmtInvalidCrossplaneState, "Stopped"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.state.Validate()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCrossplaneAutoUpgradeSpecValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   *CrossplaneAutoUpgradeSpec
+		want   error
+	}{
+		"Nil": {
+			reason: "A nil spec should be valid.",
+		},
+		"NoConstraint": {
+			reason: "A spec without a version constraint should be valid.",
+			spec:   &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeNone)},
+		},
+		"DefaultChannelWithConstraint": {
+			reason: "A version constraint should be valid with the default channel.",
+			spec:   &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To("< 1.16.0-0")},
+		},
+		"InvalidConstraint": {
+			reason: "A version constraint that cannot be parsed should be invalid.",
+			spec:   &CrossplaneAutoUpgradeSpec{VersionConstraint: ptr.To("~> one")},
+			want:   errors.Wrapf(errors.New("improper constraint: ~> one"), errFmtParseConstraint, "~> one"),
+		},
+		"NoneWithConstraint": {
+			reason: "A version constraint should be invalid with the None channel.",
+			spec: &CrossplaneAutoUpgradeSpec{
+				Channel:           ptr.To(CrossplaneUpgradeNone),
+				VersionConstraint: ptr.To("< 1.16.0-0"),
+			},
+			want: errors.New(errConstraintWithChannelNone),
+		},
+		"RapidWithRange": {
+			reason: "A version range should be valid with the Rapid channel.",
+			spec: &CrossplaneAutoUpgradeSpec{
+				Channel:           ptr.To(CrossplaneUpgradeRapid),
+				VersionConstraint: ptr.To(">= 1.14.0-0, < 1.16.0-0"),
+			},
+		},
+		"RapidWithExactVersion": {
+			reason: "An exact version pin should be invalid with the Rapid channel.",
+			spec: &CrossplaneAutoUpgradeSpec{
+				Channel:           ptr.To(CrossplaneUpgradeRapid),
+				VersionConstraint: ptr.To("1.15.0-up.1"),
+			},
+			want: errors.Errorf(errFmtExactConstraintWithRapid, "1.15.0-up.1"),
+		},
+		"RapidWithEqualsVersion": {
+			reason: "An exact version pin using the equals operator should be invalid with the Rapid channel.",
+			spec: &CrossplaneAutoUpgradeSpec{
+				Channel:           ptr.To(CrossplaneUpgradeRapid),
+				VersionConstraint: ptr.To("= 1.15.0"),
+			},
+			want: errors.Errorf(errFmtExactConstraintWithRapid, "= 1.15.0"),
+		},
+		"PatchWithExactVersion": {
+			reason: "An exact version pin should be valid with the Patch channel.",
+			spec: &CrossplaneAutoUpgradeSpec{
+				Channel:           ptr.To(CrossplaneUpgradePatch),
+				VersionConstraint: ptr.To("1.15.0"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidate(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateNoDowngrade(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		old    *CrossplaneSpec
+		new    CrossplaneSpec
+		want   error
+	}{
+		"NoOldSpec": {
+			reason: "A spec without an old spec, e.g., on creation, should be valid.",
+			new:    CrossplaneSpec{Version: ptr.To("1.14.0-up.1")},
+		},
+		"NoOldVersion": {
+			reason: "Setting a version on a spec without one should be valid.",
+			old:    &CrossplaneSpec{},
+			new:    CrossplaneSpec{Version: ptr.To("1.14.0-up.1")},
+		},
+		"NoNewVersion": {
+			reason: "Unsetting the version in favor of the upgrade channel should be valid.",
+			old:    &CrossplaneSpec{Version: ptr.To("1.15.0-up.1")},
+			new:    CrossplaneSpec{AutoUpgradeSpec: &CrossplaneAutoUpgradeSpec{Channel: ptr.To(CrossplaneUpgradeRapid)}},
+		},
+		"Unchanged": {
+			reason: "An unchanged version should be valid.",
+			old:    &CrossplaneSpec{Version: ptr.To("1.15.0-up.1")},
+			new:    CrossplaneSpec{Version: ptr.To("1.15.0-up.1")},
+		},
+		"Upgrade": {
+			reason: "A higher version should be valid.",
+			old:    &CrossplaneSpec{Version: ptr.To("1.14.8-up.1")},
+			new:    CrossplaneSpec{Version: ptr.To("1.15.0-up.1")},
+		},
+		"PrereleaseUpgrade": {
+			reason: "A higher prerelease of the same version should be valid.",
+			old:    &CrossplaneSpec{Version: ptr.To("1.15.0-up.1")},
+			new:    CrossplaneSpec{Version: ptr.To("1.15.0-up.2")},
+		},
+		"Downgrade": {
+			reason: "A lower version should be invalid.",
+			old:    &CrossplaneSpec{Version: ptr.To("1.15.0-up.1")},
+			new:    CrossplaneSpec{Version: ptr.To("1.14.8-up.1")},
+			want:   errors.Errorf(errFmtDowngrade, "1.15.0-up.1", "1.14.8-up.1"),
+		},
+		"InvalidVersion": {
+			reason: "An unparsable version should be invalid.",
+			old:    &CrossplaneSpec{Version: ptr.To("1.15.0-up.1")},
+			new:    CrossplaneSpec{Version: ptr.To("latest")},
+			want:   errors.Wrapf(semver.ErrInvalidSemVer, errFmtParseVersion, "latest"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.new.ValidateNoDowngrade(tc.old)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateNoDowngrade(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
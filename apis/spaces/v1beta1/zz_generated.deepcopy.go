@@ -24,6 +24,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionTransition) DeepCopyInto(out *ConditionTransition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionTransition.
+func (in *ConditionTransition) DeepCopy() *ConditionTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlane) DeepCopyInto(out *ControlPlane) {
 	*out = *in
@@ -113,6 +129,13 @@ func (in *ControlPlaneSpec) DeepCopy() *ControlPlaneSpec {
 func (in *ControlPlaneStatus) DeepCopyInto(out *ControlPlaneStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.ConditionHistory != nil {
+		in, out := &in.ConditionHistory, &out.ConditionHistory
+		*out = make([]ConditionTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneStatus.
@@ -133,6 +156,11 @@ func (in *CrossplaneAutoUpgradeSpec) DeepCopyInto(out *CrossplaneAutoUpgradeSpec
 		*out = new(CrossplaneUpgradeChannel)
 		**out = **in
 	}
+	if in.VersionConstraint != nil {
+		in, out := &in.VersionConstraint, &out.VersionConstraint
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossplaneAutoUpgradeSpec.